@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/massdriver-cloud/airlock/docs/helpdocs"
@@ -24,6 +23,9 @@ func NewCmdBicep() *cobra.Command {
 		Long:  helpdocs.MustRender("bicep/input"),
 		RunE:  runBicepInput,
 	}
+	addOutputFormatFlag(bicepInputCmd)
+	addDiagnosticFlags(bicepInputCmd)
+	addOutputFileFlags(bicepInputCmd)
 
 	// Output
 	bicepOutputCmd := &cobra.Command{
@@ -33,9 +35,23 @@ func NewCmdBicep() *cobra.Command {
 		Long:  helpdocs.MustRender("bicep/output"),
 		RunE:  runBicepOutput,
 	}
+	addOutputFormatFlag(bicepOutputCmd)
+	addDiagnosticFlags(bicepOutputCmd)
+	addOutputFileFlags(bicepOutputCmd)
+
+	// Check
+	bicepCheckCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check a Bicep template's params satisfy a JSON Schema contract",
+		Args:  cobra.ExactArgs(1),
+		Long:  helpdocs.MustRender("bicep/check"),
+		RunE:  runBicepCheck,
+	}
+	addCheckFlags(bicepCheckCmd)
 
 	bicepCmd.AddCommand(bicepInputCmd)
 	bicepCmd.AddCommand(bicepOutputCmd)
+	bicepCmd.AddCommand(bicepCheckCmd)
 
 	return bicepCmd
 }
@@ -43,10 +59,7 @@ func NewCmdBicep() *cobra.Command {
 func runBicepInput(cmd *cobra.Command, args []string) error {
 	result := bicep.BicepToSchema(args[0])
 
-	fmt.Print(result.PrettyDiags())
-	fmt.Print(result.PrettySchema())
-
-	return nil
+	return printSchemaResult(cmd, &result, "schema.json")
 }
 
 func runBicepOutput(cmd *cobra.Command, args []string) error {
@@ -64,11 +77,16 @@ func runBicepOutput(cmd *cobra.Command, args []string) error {
 		defer in.Close()
 	}
 
-	bytes, err := bicep.SchemaToBicep(in)
+	result, err := bicep.SchemaToBicepWithDiagnostics(in)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("%s", bytes)
-	return nil
+	return printCodeResult(cmd, result, "params.bicep")
+}
+
+func runBicepCheck(cmd *cobra.Command, args []string) error {
+	result := bicep.BicepToSchema(args[0])
+
+	return runCheck(cmd, args[0], &result)
 }
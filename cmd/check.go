@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/pkg/contract"
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+func addCheckFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("schema", "s", "schema.json", "Path to the JSON Schema contract the module has to satisfy")
+	addOutputFormatFlag(cmd)
+	addDiagnosticFlags(cmd)
+}
+
+// Compare the schema generated from a module to the --schema contract. Diagnostics from generating the schema are
+// reported along with the ones from the comparison
+func runCheck(cmd *cobra.Command, modulePath string, moduleResult *result.SchemaResult) error {
+	outputFormat, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	contractPath, _ := cmd.Flags().GetString("schema")
+	contractSchema, err := schema.Load(contractPath)
+	if err != nil {
+		return err
+	}
+
+	diags := moduleResult.Diags
+	if moduleResult.Schema != nil {
+		checkDiags := contract.Check(contractSchema, moduleResult.Schema)
+		for index := range checkDiags {
+			locateCheckDiagnostic(&checkDiags[index], moduleResult.Locations, modulePath, contractPath)
+		}
+		diags = append(diags, checkDiags...)
+	}
+	diags, err = filterDiagnostics(cmd, diags)
+	if err != nil {
+		return err
+	}
+	if reportErr := writeReports(cmd, diags); reportErr != nil {
+		return reportErr
+	}
+
+	if outputFormat == "json" {
+		output, jsonErr := result.DiagnosticsJSON(diags)
+		if jsonErr != nil {
+			return jsonErr
+		}
+		fmt.Print(output)
+	} else {
+		fmt.Print(result.Pretty(diags, "The module satisfies the contract"))
+	}
+
+	return failOnDiagnostics(cmd, diags)
+}
+
+// Point a diagnostic at the declaration in the module of the property it's about, or of the closest parent the
+// converter knows the location of. Properties the module doesn't have are located in the contract instead, by
+// their schema pointer
+func locateCheckDiagnostic(diag *result.Diagnostic, locations map[string]result.Location, modulePath, contractPath string) {
+	for pointer := diag.SchemaPointer; pointer != ""; pointer = pointer[:strings.LastIndex(pointer, "/")] {
+		if location, exists := locations[pointer]; exists {
+			diag.File = location.File
+			diag.Range = location.Range
+			return
+		}
+	}
+	if diag.Code == "extra_variable" {
+		diag.File = modulePath
+		return
+	}
+	diag.File = contractPath
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/spf13/cobra"
+)
+
+var failOnLevels = []string{"warning", "error", "none"}
+
+// Exit codes, so pipelines can tell a command that couldn't run apart from one that found problems
+const (
+	exitFailure  = 1
+	exitWarnings = 2
+	exitErrors   = 3
+)
+
+// diagnosticsError is returned when a command finds diagnostics at or above the --fail-on level. The diagnostics
+// have already been printed, so it only carries what's needed for the exit code
+type diagnosticsError struct {
+	count  int
+	level  result.Severity
+	failOn string
+}
+
+func (e *diagnosticsError) Error() string {
+	return fmt.Sprintf("%d diagnostic(s) at or above the %s level", e.count, e.failOn)
+}
+
+func addDiagnosticFlags(cmd *cobra.Command) {
+	cmd.Flags().String("fail-on", "error", "Exit with a non-zero status if there are diagnostics at or above this level ("+strings.Join(failOnLevels, ", ")+")")
+	cmd.Flags().StringSlice("ignore", nil, "Diagnostic codes to ignore")
+	addReportFlag(cmd)
+}
+
+// Check the diagnostic flags and drop the ignored diagnostics
+func filterDiagnostics(cmd *cobra.Command, diags []result.Diagnostic) ([]result.Diagnostic, error) {
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	if !slices.Contains(failOnLevels, failOn) {
+		return nil, fmt.Errorf("unknown --fail-on level %q", failOn)
+	}
+
+	ignore, _ := cmd.Flags().GetStringSlice("ignore")
+	return result.Suppress(diags, ignore), nil
+}
+
+// Returns a diagnosticsError if any of the diagnostics are at or above the --fail-on level
+func failOnDiagnostics(cmd *cobra.Command, diags []result.Diagnostic) error {
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	if failOn == "none" {
+		return nil
+	}
+
+	failure := diagnosticsError{failOn: failOn}
+	for _, diag := range diags {
+		if diag.Level == result.Error || failOn == "warning" {
+			failure.count++
+			if failure.level != result.Error {
+				failure.level = diag.Level
+			}
+		}
+	}
+	if failure.count == 0 {
+		return nil
+	}
+
+	// the diagnostics have already been printed, so the usage would just be noise
+	cmd.SilenceUsage = true
+	return &failure
+}
+
+func exitCode(err error) int {
+	var diagErr *diagnosticsError
+	if !errors.As(err, &diagErr) {
+		return exitFailure
+	}
+	if diagErr.level == result.Error {
+		return exitErrors
+	}
+	return exitWarnings
+}
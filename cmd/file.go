@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/pkg/generated"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+func addOutputFileFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", "", "Write to this file instead of stdout. If it's a directory the file gets a default name")
+	cmd.Flags().Bool("force", false, "Overwrite the output file even if it wasn't generated by airlock or has been edited since")
+	cmd.Flags().Bool("check", false, "Don't write the output file, fail if it differs from what would be generated")
+}
+
+// The file to write to, "" for stdout. Directories (existing ones, or anything ending in a slash) get the default
+// file name for the command
+func getOutputPath(cmd *cobra.Command, defaultFilename string) (string, error) {
+	path, _ := cmd.Flags().GetString("output")
+	check, _ := cmd.Flags().GetBool("check")
+	if path == "" {
+		if check {
+			return "", errors.New("--check needs a file to check, set with --output")
+		}
+		return "", nil
+	}
+
+	if strings.HasSuffix(path, "/") || strings.HasSuffix(path, string(os.PathSeparator)) {
+		return filepath.Join(path, defaultFilename), nil
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return filepath.Join(path, defaultFilename), nil
+	}
+	return path, nil
+}
+
+// Write the content to the output file, or with --check make sure the file already has exactly that content.
+// Files that weren't generated by airlock, or were edited after, are only overwritten with --force
+func writeOutputFile(cmd *cobra.Command, path string, content []byte) error {
+	force, _ := cmd.Flags().GetBool("force")
+	check, _ := cmd.Flags().GetBool("check")
+
+	existing, readErr := os.ReadFile(path)
+	exists := readErr == nil
+	if readErr != nil && !errors.Is(readErr, os.ErrNotExist) {
+		return readErr
+	}
+
+	if check {
+		if !exists || !bytes.Equal(existing, content) {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%s is out of date, run the command without --check to regenerate it", path)
+		}
+		return nil
+	}
+
+	if exists && bytes.Equal(existing, content) {
+		return nil
+	}
+	if exists && !force && generated.GetStatus(existing) != generated.Unmodified {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("refusing to overwrite %s because it wasn't generated by airlock or has been edited since, use --force to overwrite it", path)
+	}
+
+	if mkdirErr := os.MkdirAll(filepath.Dir(path), 0o755); mkdirErr != nil {
+		return mkdirErr
+	}
+	return generated.WriteFile(path, content, 0o644)
+}
+
+// The marker comment for code, in the comment syntax of the file
+func codeMarker(path string) string {
+	if filepath.Ext(path) == ".bicep" {
+		return "// " + generated.Marker + "\n\n"
+	}
+	return "# " + generated.Marker + "\n\n"
+}
+
+// Schemas don't have comments, so the marker goes in the root $comment of a copy of the schema. A $comment of the
+// user's keeps its text, the marker is appended on its own line (replacing the marker of an earlier run) so it can
+// be removed again with generated.Unmark
+func markSchema(sch *schema.Schema) *schema.Schema {
+	marked := *sch
+	if comment := generated.Unmark(sch.Comment); comment != "" {
+		marked.Comment = comment + "\n" + generated.Marker
+	} else {
+		marked.Comment = generated.Marker
+	}
+	return &marked
+}
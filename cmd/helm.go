@@ -1,10 +1,9 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/massdriver-cloud/airlock/docs/helpdocs"
 	"github.com/massdriver-cloud/airlock/pkg/helm"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
 	"github.com/spf13/cobra"
 )
 
@@ -23,8 +22,22 @@ func NewCmdHelm() *cobra.Command {
 		Long:  helpdocs.MustRender("helm/input"),
 		RunE:  runHelmInput,
 	}
+	addOutputFormatFlag(helmInputCmd)
+	addDiagnosticFlags(helmInputCmd)
+	addOutputFileFlags(helmInputCmd)
+
+	// Check
+	helmCheckCmd := &cobra.Command{
+		Use:   `check`,
+		Short: "Check a helm values.yaml file satisfies a JSON Schema contract",
+		Args:  cobra.ExactArgs(1),
+		Long:  helpdocs.MustRender("helm/check"),
+		RunE:  runHelmCheck,
+	}
+	addCheckFlags(helmCheckCmd)
 
 	helmCmd.AddCommand(helmInputCmd)
+	helmCmd.AddCommand(helmCheckCmd)
 
 	return helmCmd
 }
@@ -32,8 +45,23 @@ func NewCmdHelm() *cobra.Command {
 func runHelmInput(cmd *cobra.Command, args []string) error {
 	result := helm.HelmToSchema(args[0])
 
-	fmt.Print(result.PrettyDiags())
-	fmt.Print(result.PrettySchema())
+	return printSchemaResult(cmd, &result, "values.schema.json")
+}
+
+func runHelmCheck(cmd *cobra.Command, args []string) error {
+	result := helm.HelmToSchema(args[0])
+
+	// every value in values.yaml is a default, even a null or an empty array, so a chart never requires a value
+	if result.Schema != nil {
+		if _, err := schema.Walk(result.Schema, schema.Visitor{
+			Pre: func(_ string, node *schema.Schema) (*schema.Schema, error) {
+				node.Required = nil
+				return node, nil
+			},
+		}); err != nil {
+			return err
+		}
+	}
 
-	return nil
+	return runCheck(cmd, args[0], &result)
 }
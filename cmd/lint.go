@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/massdriver-cloud/airlock/docs/helpdocs"
+	"github.com/massdriver-cloud/airlock/pkg/lint"
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdLint() *cobra.Command {
+	lintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check a JSON Schema for common problems",
+		Args:  cobra.ExactArgs(1),
+		Long:  helpdocs.MustRender("lint"),
+		RunE:  runLint,
+	}
+	lintCmd.Flags().StringSlice("enable", nil, "Only run these rules")
+	lintCmd.Flags().StringSlice("disable", nil, "Don't run these rules")
+	addOutputFormatFlag(lintCmd)
+	addDiagnosticFlags(lintCmd)
+
+	return lintCmd
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	enable, _ := cmd.Flags().GetStringSlice("enable")
+	disable, _ := cmd.Flags().GetStringSlice("disable")
+	outputFormat, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	sch, err := schema.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	diags, err := lint.Lint(sch, lint.Config{Enable: enable, Disable: disable})
+	if err != nil {
+		return err
+	}
+	diags, err = filterDiagnostics(cmd, diags)
+	if err != nil {
+		return err
+	}
+
+	if reportErr := writeReports(cmd, diags); reportErr != nil {
+		return reportErr
+	}
+
+	if outputFormat == "json" {
+		output, jsonErr := result.DiagnosticsJSON(diags)
+		if jsonErr != nil {
+			return jsonErr
+		}
+		fmt.Print(output)
+	} else {
+		fmt.Print(result.Pretty(diags, "No problems found"))
+	}
+
+	return failOnDiagnostics(cmd, diags)
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/massdriver-cloud/airlock/docs/helpdocs"
@@ -25,6 +24,23 @@ func NewCmdOpenTofu() *cobra.Command {
 		Long:  helpdocs.MustRender("opentofu/input"),
 		RunE:  runOpenTofuInput,
 	}
+	opentofuInputCmd.Flags().Bool("alphabetical", false, "Order properties alphabetically instead of by where they are declared in the module")
+	addOutputFormatFlag(opentofuInputCmd)
+	addDiagnosticFlags(opentofuInputCmd)
+	addOutputFileFlags(opentofuInputCmd)
+
+	// Outputs
+	opentofuOutputsCmd := &cobra.Command{
+		Use:   `outputs`,
+		Short: "Ingest an OpenTofu module and generate a JSON Schema from the outputs",
+		Args:  cobra.ExactArgs(1),
+		Long:  helpdocs.MustRender("opentofu/outputs"),
+		RunE:  runOpenTofuOutputs,
+	}
+	opentofuOutputsCmd.Flags().Bool("alphabetical", false, "Order properties alphabetically instead of by where they are declared in the module")
+	addOutputFormatFlag(opentofuOutputsCmd)
+	addDiagnosticFlags(opentofuOutputsCmd)
+	addOutputFileFlags(opentofuOutputsCmd)
 
 	// Output
 	opentofuOutputCmd := &cobra.Command{
@@ -34,20 +50,48 @@ func NewCmdOpenTofu() *cobra.Command {
 		Long:  helpdocs.MustRender("opentofu/output"),
 		RunE:  runOpenTofuOutput,
 	}
+	addOutputFormatFlag(opentofuOutputCmd)
+	addDiagnosticFlags(opentofuOutputCmd)
+	addOutputFileFlags(opentofuOutputCmd)
+
+	// Check
+	opentofuCheckCmd := &cobra.Command{
+		Use:   `check`,
+		Short: "Check an OpenTofu module's variables satisfy a JSON Schema contract",
+		Args:  cobra.ExactArgs(1),
+		Long:  helpdocs.MustRender("opentofu/check"),
+		RunE:  runOpenTofuCheck,
+	}
+	addCheckFlags(opentofuCheckCmd)
 
 	opentofuCmd.AddCommand(opentofuInputCmd)
+	opentofuCmd.AddCommand(opentofuOutputsCmd)
 	opentofuCmd.AddCommand(opentofuOutputCmd)
+	opentofuCmd.AddCommand(opentofuCheckCmd)
 
 	return opentofuCmd
 }
 
 func runOpenTofuInput(cmd *cobra.Command, args []string) error {
-	result := opentofu.TofuToSchema(args[0])
+	result := opentofu.TofuToSchema(args[0], openTofuOptions(cmd)...)
+
+	return printSchemaResult(cmd, &result, "schema.json")
+}
 
-	fmt.Print(result.PrettyDiags())
-	fmt.Print(result.PrettySchema())
+func runOpenTofuOutputs(cmd *cobra.Command, args []string) error {
+	result := opentofu.OutputsToSchema(args[0], openTofuOptions(cmd)...)
 
-	return nil
+	return printSchemaResult(cmd, &result, "outputs.schema.json")
+}
+
+func openTofuOptions(cmd *cobra.Command) []opentofu.Option {
+	alphabetical, _ := cmd.Flags().GetBool("alphabetical")
+
+	opts := []opentofu.Option{}
+	if alphabetical {
+		opts = append(opts, opentofu.WithAlphabeticalOrder())
+	}
+	return opts
 }
 
 func runOpenTofuOutput(cmd *cobra.Command, args []string) error {
@@ -65,12 +109,17 @@ func runOpenTofuOutput(cmd *cobra.Command, args []string) error {
 		defer in.Close()
 	}
 
-	bytes, err := opentofu.SchemaToTofu(in)
+	result, err := opentofu.SchemaToTofuWithDiagnostics(in)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("%s", bytes)
+	return printCodeResult(cmd, result, "variables.tf")
+}
+
+func runOpenTofuCheck(cmd *cobra.Command, args []string) error {
+	// OpenTofu variables accept null unless they set nullable = false
+	result := opentofu.TofuToSchema(args[0], opentofu.WithImplicitNullable())
 
-	return nil
+	return runCheck(cmd, args[0], &result)
 }
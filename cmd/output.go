@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/pkg/generated"
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/spf13/cobra"
+)
+
+var outputFormats = []string{"text", "json"}
+
+func addOutputFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String("output-format", "text", "Output format ("+strings.Join(outputFormats, ", ")+")")
+}
+
+func getOutputFormat(cmd *cobra.Command) (string, error) {
+	outputFormat, _ := cmd.Flags().GetString("output-format")
+	if !slices.Contains(outputFormats, outputFormat) {
+		return "", fmt.Errorf("unknown output format %q", outputFormat)
+	}
+	return outputFormat, nil
+}
+
+// Print the schema to stdout, or write it to the --output file, along with the diagnostics in json mode. In text
+// mode the diagnostics go to stderr so the schema can still be piped
+func printSchemaResult(cmd *cobra.Command, schemaResult *result.SchemaResult, defaultFilename string) error {
+	outputFormat, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	outputPath, err := getOutputPath(cmd, defaultFilename)
+	if err != nil {
+		return err
+	}
+	schemaResult.Diags, err = filterDiagnostics(cmd, schemaResult.Diags)
+	if err != nil {
+		return err
+	}
+	if reportErr := writeReports(cmd, schemaResult.Diags); reportErr != nil {
+		return reportErr
+	}
+
+	// only the written file is marked, what's printed is the schema as it was generated
+	var content []byte
+	if outputPath != "" && schemaResult.Schema != nil {
+		marked := result.SchemaResult{Schema: markSchema(schemaResult.Schema)}
+		content = generated.Sign([]byte(marked.PrettySchema() + "\n"))
+	}
+
+	if outputFormat == "json" {
+		output, jsonErr := schemaResult.JSON()
+		if jsonErr != nil {
+			return jsonErr
+		}
+		fmt.Print(output)
+	} else {
+		fmt.Fprint(os.Stderr, schemaResult.PrettyDiags())
+		if outputPath == "" {
+			fmt.Print(schemaResult.PrettySchema())
+		}
+	}
+
+	if outputPath != "" {
+		// the diagnostics explain why there's no schema, but they might not be failing ones
+		if schemaResult.Schema == nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("no schema was generated, so there's nothing to write to %s", outputPath)
+		}
+		if writeErr := writeOutputFile(cmd, outputPath, content); writeErr != nil {
+			return writeErr
+		}
+	}
+	return failOnDiagnostics(cmd, schemaResult.Diags)
+}
+
+// Print the code to stdout, or write it to the --output file, along with the diagnostics in json mode. In text mode
+// the diagnostics go to stderr so the code can be redirected to a file
+func printCodeResult(cmd *cobra.Command, codeResult *result.CodeResult, defaultFilename string) error {
+	outputFormat, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	outputPath, err := getOutputPath(cmd, defaultFilename)
+	if err != nil {
+		return err
+	}
+	codeResult.Diags, err = filterDiagnostics(cmd, codeResult.Diags)
+	if err != nil {
+		return err
+	}
+	if reportErr := writeReports(cmd, codeResult.Diags); reportErr != nil {
+		return reportErr
+	}
+
+	if outputFormat == "json" {
+		output, jsonErr := codeResult.JSON()
+		if jsonErr != nil {
+			return jsonErr
+		}
+		fmt.Print(output)
+	} else {
+		fmt.Fprint(os.Stderr, codeResult.PrettyDiags())
+		if outputPath == "" {
+			fmt.Printf("%s", codeResult.Code)
+		}
+	}
+
+	if outputPath != "" {
+		content := generated.Sign(append([]byte(codeMarker(outputPath)), codeResult.Code...))
+		if writeErr := writeOutputFile(cmd, outputPath, content); writeErr != nil {
+			return writeErr
+		}
+	}
+	return failOnDiagnostics(cmd, codeResult.Diags)
+}
@@ -0,0 +1,81 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/cmd"
+	"github.com/massdriver-cloud/airlock/pkg/generated"
+	"github.com/massdriver-cloud/airlock/pkg/opentofu"
+	"github.com/stretchr/testify/require"
+)
+
+const modulePath = "../pkg/opentofu/testdata/opentofu/simple"
+
+// Run the opentofu command with the arguments and return what it printed to stdout
+func runOpenTofu(t *testing.T, args ...string) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	command := cmd.NewCmdOpenTofu()
+	command.SetArgs(args)
+	runErr := command.Execute()
+	require.NoError(t, writer.Close())
+	require.NoError(t, runErr)
+
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(output)
+}
+
+func TestPrintSchemaResult(t *testing.T) {
+	generatedResult := opentofu.TofuToSchema(modulePath)
+	unmarked := generatedResult.PrettySchema()
+
+	t.Run("stdout", func(t *testing.T) {
+		got := runOpenTofu(t, "input", modulePath)
+
+		require.Equal(t, unmarked, got)
+	})
+
+	t.Run("output file", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "schema.json")
+
+		got := runOpenTofu(t, "input", modulePath, "-o", outputPath, "--output-format", "json")
+
+		var printed struct {
+			Schema json.RawMessage `json:"schema"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(got), &printed))
+		require.JSONEq(t, unmarked, string(printed.Schema))
+
+		written, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		require.Equal(t, generated.Unmodified, generated.GetStatus(written))
+
+		var writtenSchema struct {
+			Comment string `json:"$comment"`
+		}
+		require.NoError(t, json.Unmarshal(written, &writtenSchema))
+		require.Contains(t, writtenSchema.Comment, generated.Marker)
+		require.Empty(t, generated.Unmark(writtenSchema.Comment))
+	})
+	t.Run("no schema", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "schema.json")
+
+		command := cmd.NewCmdOpenTofu()
+		command.SetArgs([]string{"input", filepath.Join(t.TempDir(), "missing"), "-o", outputPath, "--fail-on", "none"})
+		command.SetErr(io.Discard)
+		err := command.Execute()
+		require.ErrorContains(t, err, "no schema was generated")
+		require.NoFileExists(t, outputPath)
+	})
+}
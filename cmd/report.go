@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/spf13/cobra"
+)
+
+var reportFormats = []string{"sarif", "junit"}
+
+// Only commands that produce diagnostics take --report, so it's never silently ignored
+func addReportFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray("report", nil, "Also write the diagnostics to a file, as <format>=<path> ("+strings.Join(reportFormats, ", ")+"). Can be repeated")
+}
+
+type report struct {
+	format string
+	path   string
+}
+
+func getReports(cmd *cobra.Command) ([]report, error) {
+	flags, _ := cmd.Flags().GetStringArray("report")
+	reports := []report{}
+	for _, flag := range flags {
+		format, path, found := strings.Cut(flag, "=")
+		if !found || path == "" {
+			return nil, fmt.Errorf("invalid report %q, expected <format>=<path>", flag)
+		}
+		if !slices.Contains(reportFormats, format) {
+			return nil, fmt.Errorf("unknown report format %q", format)
+		}
+		reports = append(reports, report{format: format, path: path})
+	}
+	return reports, nil
+}
+
+// Write the diagnostics to each of the report files requested with --report
+func writeReports(cmd *cobra.Command, diags []result.Diagnostic) error {
+	reports, err := getReports(cmd)
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		var output string
+		var renderErr error
+		switch report.format {
+		case "sarif":
+			output, renderErr = result.SARIF(diags)
+		case "junit":
+			output, renderErr = result.JUnit(cmd.CommandPath(), diags)
+		}
+		if renderErr != nil {
+			return renderErr
+		}
+		if writeErr := os.WriteFile(report.path, []byte(output), 0o644); writeErr != nil {
+			return fmt.Errorf("failed to write %s report: %w", report.format, writeErr)
+		}
+	}
+	return nil
+}
@@ -34,13 +34,12 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	rootCmd.AddCommand(NewCmdBicep())
 	rootCmd.AddCommand(NewCmdHelm())
+	rootCmd.AddCommand(NewCmdLint())
 	rootCmd.AddCommand(NewCmdOpenTofu())
+	rootCmd.AddCommand(NewCmdSchema())
 	rootCmd.AddCommand(NewCmdValidate())
 	rootCmd.AddCommand(NewCmdVersion())
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
-
-func init() {
-}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/docs/helpdocs"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/massdriver-cloud/airlock/pkg/schema/diff"
+	"github.com/massdriver-cloud/airlock/pkg/schema/draft"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdSchema() *cobra.Command {
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "JSON Schema utilities",
+		Long:  helpdocs.MustRender("schema"),
+	}
+
+	// Convert
+	targets := make([]string, len(draft.Targets))
+	for i, target := range draft.Targets {
+		targets[i] = string(target)
+	}
+	schemaConvertCmd := &cobra.Command{
+		Use:   "convert",
+		Short: "Convert a JSON Schema document to another draft",
+		Args:  cobra.ExactArgs(1),
+		Long:  helpdocs.MustRender("schema/convert"),
+		RunE:  runSchemaConvert,
+	}
+	schemaConvertCmd.Flags().String("to", string(draft.Draft202012), "Draft to convert to ("+strings.Join(targets, ", ")+")")
+
+	// Diff
+	schemaDiffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare two versions of a JSON Schema and classify the changes as breaking or non-breaking",
+		Args:  cobra.ExactArgs(2),
+		Long:  helpdocs.MustRender("schema/diff"),
+		RunE:  runSchemaDiff,
+	}
+	addOutputFormatFlag(schemaDiffCmd)
+	addDiagnosticFlags(schemaDiffCmd)
+
+	schemaCmd.AddCommand(schemaConvertCmd)
+	schemaCmd.AddCommand(schemaDiffCmd)
+
+	return schemaCmd
+}
+
+func runSchemaConvert(cmd *cobra.Command, args []string) error {
+	to, _ := cmd.Flags().GetString("to")
+	target, err := draft.Parse(to)
+	if err != nil {
+		return err
+	}
+
+	schemaPath := args[0]
+
+	var in *os.File
+	if schemaPath == "-" {
+		in = os.Stdin
+	} else {
+		in, err = os.Open(schemaPath)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+	}
+
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := draft.Convert(data, target)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", bytes)
+	return nil
+}
+
+func runSchemaDiff(cmd *cobra.Command, args []string) error {
+	outputFormat, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	before, err := schema.Load(args[0])
+	if err != nil {
+		return err
+	}
+	after, err := schema.Load(args[1])
+	if err != nil {
+		return err
+	}
+
+	changes := diff.Compare(before, after)
+	breaking := diff.HasBreaking(changes)
+
+	diags, err := filterDiagnostics(cmd, diff.Diagnostics(changes))
+	if err != nil {
+		return err
+	}
+	if reportErr := writeReports(cmd, diags); reportErr != nil {
+		return reportErr
+	}
+
+	if outputFormat == "json" {
+		bytes, marshalErr := json.MarshalIndent(map[string]any{
+			"breaking": breaking,
+			"changes":  changes,
+		}, "", "  ")
+		if marshalErr != nil {
+			return marshalErr
+		}
+		fmt.Println(string(bytes))
+	} else {
+		fmt.Print(diff.Pretty(changes))
+	}
+
+	return failOnDiagnostics(cmd, diags)
+}
@@ -1,10 +1,11 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
+	"os"
 
 	"github.com/massdriver-cloud/airlock/docs/helpdocs"
+	"github.com/massdriver-cloud/airlock/pkg/result"
 	"github.com/massdriver-cloud/airlock/pkg/validate"
 	"github.com/spf13/cobra"
 )
@@ -12,12 +13,13 @@ import (
 func NewCmdValidate() *cobra.Command {
 	validateCmd := &cobra.Command{
 		Use:   "validate",
-		Short: "Validate a document matches a JSON Schema",
+		Short: "Validate a JSON, YAML or IaC parameter file matches a JSON Schema",
 		RunE:  runValidate,
 		Long:  helpdocs.MustRender("validate"),
 	}
-	validateCmd.Flags().StringP("document", "d", "document.json", "Path to document")
+	validateCmd.Flags().StringP("document", "d", "document.json", "Path to document (JSON, YAML, .tfvars, .bicepparam or ARM parameters)")
 	validateCmd.Flags().StringP("schema", "s", "./schema.json", "Path to JSON Schema")
+	addDiagnosticFlags(validateCmd)
 
 	return validateCmd
 }
@@ -26,20 +28,40 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	schema, _ := cmd.Flags().GetString("schema")
 	document, _ := cmd.Flags().GetString("document")
 
-	result, err := validate.Validate(schema, document)
+	results, err := validate.ValidateDocuments(schema, document)
 	if err != nil {
 		return err
 	}
 
-	if result.Valid() {
-		fmt.Println("The document is valid!")
-	} else {
-		errMsg := fmt.Sprintf("The document failed validation:\n\tDocument: %s\n\tSchema: %s\nErrors:\n", document, schema)
-		for _, violation := range result.Errors() {
-			errMsg += fmt.Sprintf("\t- %v\n", violation)
+	diags := []result.Diagnostic{}
+	for _, documentResult := range results {
+		diags = append(diags, documentResult.Diagnostics()...)
+	}
+	diags, err = filterDiagnostics(cmd, diags)
+	if err != nil {
+		return err
+	}
+	if reportErr := writeReports(cmd, diags); reportErr != nil {
+		return reportErr
+	}
+
+	if len(diags) == 0 {
+		if len(results) == 1 {
+			fmt.Println("The document is valid!")
+		} else {
+			fmt.Printf("All %d documents are valid!\n", len(results))
 		}
+		return nil
+	}
 
-		return errors.New(errMsg)
+	errMsg := fmt.Sprintf("The document failed validation:\n\tDocument: %s\n\tSchema: %s\nErrors:\n", document, schema)
+	for _, diag := range diags {
+		violation := fmt.Sprintf("%s: %s", diag.Path, diag.Message)
+		if diag.Range != nil {
+			violation = fmt.Sprintf("%s: %s", diag.Location(), violation)
+		}
+		errMsg += fmt.Sprintf("\t- %s\n", violation)
 	}
-	return nil
+	fmt.Fprint(os.Stderr, errMsg)
+	return failOnDiagnostics(cmd, diags)
 }
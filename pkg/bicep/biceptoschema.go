@@ -48,37 +48,46 @@ func BicepToSchema(templatePath string) result.SchemaResult {
 					Code:    "file_read_error",
 					Message: fmt.Sprintf("failed to read bicep file: %s", parseErr),
 					Level:   result.Error,
+					File:    templatePath,
 				},
 			},
 		}
 	}
 
 	output := result.SchemaResult{
-		Schema: sch,
-		Diags:  []result.Diagnostic{},
+		Schema:    sch,
+		Diags:     []result.Diagnostic{},
+		Locations: map[string]result.Location{},
 	}
 
 	for name, value := range doc[0]["parameters"].(map[string]interface{}) {
 		param := new(bicepParam)
+		pointer := schema.AppendPointer("", "properties", name)
+		line := kicsLine(value, "type")
+		output.Locations[pointer] = result.Location{File: templatePath, Range: lineRange(line)}
 
 		// marshal to json and unmarshal into custom struct to make bicep param easier to access
 		bytes, marshalErr := json.Marshal(value)
 		if marshalErr != nil {
 			output.Diags = append(output.Diags, result.Diagnostic{
-				Path:    name,
-				Code:    "invalid_value",
-				Message: fmt.Sprintf("failed to marshal bicep param %s: %s", name, marshalErr),
-				Level:   result.Error,
+				Path:          name,
+				Code:          "invalid_value",
+				Message:       fmt.Sprintf("failed to marshal bicep param %s: %s", name, marshalErr),
+				Level:         result.Error,
+				Range:         lineRange(line),
+				SchemaPointer: pointer,
 			})
 			continue
 		}
 		unmarshalErr := json.Unmarshal(bytes, &param)
 		if unmarshalErr != nil {
 			output.Diags = append(output.Diags, result.Diagnostic{
-				Path:    name,
-				Code:    "invalid_value",
-				Message: fmt.Sprintf("failed to unmarshal bicep param %s: %s", name, unmarshalErr),
-				Level:   result.Error,
+				Path:          name,
+				Code:          "invalid_value",
+				Message:       fmt.Sprintf("failed to unmarshal bicep param %s: %s", name, unmarshalErr),
+				Level:         result.Error,
+				Range:         lineRange(line),
+				SchemaPointer: pointer,
 			})
 			continue
 		}
@@ -87,7 +96,7 @@ func BicepToSchema(templatePath string) result.SchemaResult {
 		property.Title = name
 		property.Description = param.Metadata.Description
 
-		output.Diags = parseBicepParam(property, param, output.Diags)
+		output.Diags = parseBicepParam(property, param, pointer, line, output.Diags)
 
 		sch.Properties.Set(name, property)
 		sch.Required = append(sch.Required, name)
@@ -95,36 +104,42 @@ func BicepToSchema(templatePath string) result.SchemaResult {
 	// sorting this here just to help with testing. The order doesn't matter, but to our test suite it does.
 	slices.Sort(sch.Required)
 
+	for index := range output.Diags {
+		output.Diags[index].File = templatePath
+	}
+
 	return output
 }
 
-func parseBicepParam(sch *schema.Schema, bicepParam *bicepParam, diags []result.Diagnostic) []result.Diagnostic {
+func parseBicepParam(sch *schema.Schema, bicepParam *bicepParam, pointer string, line int, diags []result.Diagnostic) []result.Diagnostic {
 	switch bicepParam.TypeString {
 	case "int":
-		return parseIntParam(sch, bicepParam, diags)
+		return parseIntParam(sch, bicepParam, pointer, line, diags)
 	case "bool":
 		parseBoolParam(sch, bicepParam)
 	case "string":
-		return parseStringParam(sch, bicepParam, false, diags)
+		return parseStringParam(sch, bicepParam, false, pointer, line, diags)
 	case "secureString":
-		return parseStringParam(sch, bicepParam, true, diags)
+		return parseStringParam(sch, bicepParam, true, pointer, line, diags)
 	case "array":
-		return parseArrayParam(sch, bicepParam, diags)
+		return parseArrayParam(sch, bicepParam, pointer, line, diags)
 	case "object", "secureObject":
-		return parseObjectParam(sch, bicepParam, diags)
+		return parseObjectParam(sch, bicepParam, pointer, line, diags)
 	default:
 		sch.Comment = fmt.Sprintf("Airlock Warning: unknown type from Bicep parameter (%s)", bicepParam.TypeString)
 		return append(diags, result.Diagnostic{
-			Path:    sch.Title,
-			Code:    "unknown_type",
-			Message: fmt.Sprintf("type of field %s is unsupported (%s)", sch.Title, bicepParam.TypeString),
-			Level:   result.Warning,
+			Path:          sch.Title,
+			Code:          "unknown_type",
+			Message:       fmt.Sprintf("type of field %s is unsupported (%s)", sch.Title, bicepParam.TypeString),
+			Level:         result.Warning,
+			Range:         lineRange(line),
+			SchemaPointer: pointer,
 		})
 	}
 	return diags
 }
 
-func parseIntParam(sch *schema.Schema, bicepParam *bicepParam, diags []result.Diagnostic) []result.Diagnostic {
+func parseIntParam(sch *schema.Schema, bicepParam *bicepParam, pointer string, line int, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "integer"
 	sch.Default = bicepParam.DefaultValue
 
@@ -136,10 +151,12 @@ func parseIntParam(sch *schema.Schema, bicepParam *bicepParam, diags []result.Di
 		} else {
 			sch.Comment = "Airlock Warning: unable to convert 'allowedValues' to enum"
 			diags = append(diags, result.Diagnostic{
-				Path:    sch.Title,
-				Code:    "invalid_value",
-				Message: fmt.Sprintf("unable to convert 'allowedValues' to enum in bicep param %s", sch.Title),
-				Level:   result.Warning,
+				Path:          sch.Title,
+				Code:          "invalid_value",
+				Message:       fmt.Sprintf("unable to convert 'allowedValues' to enum in bicep param %s", sch.Title),
+				Level:         result.Warning,
+				Range:         lineRange(line),
+				SchemaPointer: pointer,
 			})
 		}
 	}
@@ -159,7 +176,7 @@ func parseBoolParam(sch *schema.Schema, bicepParam *bicepParam) {
 	sch.Default = bicepParam.DefaultValue
 }
 
-func parseStringParam(sch *schema.Schema, bicepParam *bicepParam, secure bool, diags []result.Diagnostic) []result.Diagnostic {
+func parseStringParam(sch *schema.Schema, bicepParam *bicepParam, secure bool, pointer string, line int, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "string"
 	sch.Default = bicepParam.DefaultValue
 
@@ -175,10 +192,12 @@ func parseStringParam(sch *schema.Schema, bicepParam *bicepParam, secure bool, d
 		} else {
 			sch.Comment = "Airlock Warning: unable to convert 'allowedValues' to enum"
 			diags = append(diags, result.Diagnostic{
-				Path:    sch.Title,
-				Code:    "invalid_value",
-				Message: fmt.Sprintf("unable to convert 'allowedValues' to enum in bicep param %s", sch.Title),
-				Level:   result.Warning,
+				Path:          sch.Title,
+				Code:          "invalid_value",
+				Message:       fmt.Sprintf("unable to convert 'allowedValues' to enum in bicep param %s", sch.Title),
+				Level:         result.Warning,
+				Range:         lineRange(line),
+				SchemaPointer: pointer,
 			})
 		}
 	}
@@ -189,28 +208,28 @@ func parseStringParam(sch *schema.Schema, bicepParam *bicepParam, secure bool, d
 	return diags
 }
 
-func parseArrayParam(sch *schema.Schema, bicepParam *bicepParam, diags []result.Diagnostic) []result.Diagnostic {
+func parseArrayParam(sch *schema.Schema, bicepParam *bicepParam, pointer string, line int, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "array"
 
 	sch.MinItems = bicepParam.MinLength
 	sch.MaxItems = bicepParam.MaxLength
 
 	if bicepParam.DefaultValue != nil && len(bicepParam.DefaultValue.([]interface{})) != 0 {
-		diags = parseArrayType(sch, bicepParam.DefaultValue.([]interface{}), diags)
+		diags = parseArrayType(sch, bicepParam.DefaultValue.([]interface{}), pointer, line, diags)
 	}
 	return diags
 }
 
-func parseObjectParam(sch *schema.Schema, bicepParam *bicepParam, diags []result.Diagnostic) []result.Diagnostic {
+func parseObjectParam(sch *schema.Schema, bicepParam *bicepParam, pointer string, line int, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "object"
 
 	if bicepParam.DefaultValue != nil && len(bicepParam.DefaultValue.(map[string]interface{})) > 1 {
-		diags = parseObjectType(sch, bicepParam.DefaultValue.(map[string]interface{}), diags)
+		diags = parseObjectType(sch, bicepParam.DefaultValue.(map[string]interface{}), pointer, line, diags)
 	}
 	return diags
 }
 
-func parseObjectType(sch *schema.Schema, objValue map[string]interface{}, diags []result.Diagnostic) []result.Diagnostic {
+func parseObjectType(sch *schema.Schema, objValue map[string]interface{}, pointer string, line int, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Properties = orderedmap.New[string, *schema.Schema]()
 	sch.Required = []string{}
 
@@ -221,6 +240,11 @@ func parseObjectType(sch *schema.Schema, objValue map[string]interface{}, diags
 
 		property := new(schema.Schema)
 		property.Title = name
+		propertyPointer := schema.AppendPointer(pointer, "properties", name)
+		propertyLine := kicsLine(objValue, name)
+		if propertyLine == 0 {
+			propertyLine = line
+		}
 
 		switch reflect.TypeOf(value).Kind() {
 		case reflect.Float64:
@@ -234,17 +258,19 @@ func parseObjectType(sch *schema.Schema, objValue map[string]interface{}, diags
 			property.Default = value
 		case reflect.Slice:
 			property.Type = "array"
-			diags = parseArrayType(property, value.([]interface{}), diags)
+			diags = parseArrayType(property, value.([]interface{}), propertyPointer, propertyLine, diags)
 		case reflect.Map:
 			property.Type = "object"
-			diags = parseObjectType(property, value.(map[string]interface{}), diags)
+			diags = parseObjectType(property, value.(map[string]interface{}), propertyPointer, propertyLine, diags)
 		default:
 			sch.Comment = fmt.Sprintf("Airlock Warning: unknown type for field %s (%s)", name, reflect.TypeOf(value).Kind())
 			diags = append(diags, result.Diagnostic{
-				Path:    sch.Title,
-				Code:    "unknown_type",
-				Message: fmt.Sprintf("type of field %s is unsupported (%s)", sch.Title, reflect.TypeOf(value).Kind()),
-				Level:   result.Warning,
+				Path:          sch.Title,
+				Code:          "unknown_type",
+				Message:       fmt.Sprintf("type of field %s is unsupported (%s)", sch.Title, reflect.TypeOf(value).Kind()),
+				Level:         result.Warning,
+				Range:         lineRange(propertyLine),
+				SchemaPointer: propertyPointer,
 			})
 		}
 
@@ -256,7 +282,7 @@ func parseObjectType(sch *schema.Schema, objValue map[string]interface{}, diags
 	return diags
 }
 
-func parseArrayType(sch *schema.Schema, value []interface{}, diags []result.Diagnostic) []result.Diagnostic {
+func parseArrayType(sch *schema.Schema, value []interface{}, pointer string, line int, diags []result.Diagnostic) []result.Diagnostic {
 	if len(value) > 0 {
 		items := new(schema.Schema)
 
@@ -273,17 +299,19 @@ func parseArrayType(sch *schema.Schema, value []interface{}, diags []result.Diag
 			sch.Default = value
 		case reflect.Slice:
 			items.Type = "array"
-			diags = parseArrayType(items, elem.([]interface{}), diags)
+			diags = parseArrayType(items, elem.([]interface{}), schema.AppendPointer(pointer, "items"), line, diags)
 		case reflect.Map:
 			items.Type = "object"
-			diags = parseObjectType(items, elem.(map[string]interface{}), diags)
+			diags = parseObjectType(items, elem.(map[string]interface{}), schema.AppendPointer(pointer, "items"), line, diags)
 		default:
 			sch.Comment = fmt.Sprintf("Airlock Warning: unknown type (%s)", reflect.TypeOf(value).Kind())
 			diags = append(diags, result.Diagnostic{
-				Path:    sch.Title,
-				Code:    "unknown_type",
-				Message: fmt.Sprintf("type of field %s is unsupported (%s)", sch.Title, reflect.TypeOf(value).Kind()),
-				Level:   result.Warning,
+				Path:          sch.Title,
+				Code:          "unknown_type",
+				Message:       fmt.Sprintf("type of field %s is unsupported (%s)", sch.Title, reflect.TypeOf(value).Kind()),
+				Level:         result.Warning,
+				Range:         lineRange(line),
+				SchemaPointer: pointer,
 			})
 		}
 
@@ -291,3 +319,25 @@ func parseArrayType(sch *schema.Schema, value []interface{}, diags []result.Diag
 	}
 	return diags
 }
+
+// The line kics recorded for a key of an object it parsed, or 0 if it didn't record one
+func kicsLine(value interface{}, key string) int {
+	obj, _ := value.(map[string]interface{})
+	lines, _ := obj["_kics_lines"].(map[string]interface{})
+	entry, _ := lines["_kics_"+key].(map[string]interface{})
+	switch line := entry["_kics_line"].(type) {
+	case int:
+		return line
+	case float64:
+		return int(line)
+	}
+	return 0
+}
+
+// Kics only records lines, not columns
+func lineRange(line int) *result.Range {
+	if line == 0 {
+		return nil
+	}
+	return &result.Range{Start: result.Position{Line: line}, End: result.Position{Line: line}}
+}
@@ -166,6 +166,42 @@ func TestBicepToSchema(t *testing.T) {
 		}
 	}
 }
+`,
+		},
+		{
+			name:      "unknown type",
+			bicepPath: "testdata/unknowntype.bicep",
+			diags: []result.Diagnostic{
+				{
+					Path:    "testConfig",
+					Code:    "unknown_type",
+					Message: "type of field testConfig is unsupported (config)",
+					Level:   result.Warning,
+					File:    "testdata/unknowntype.bicep",
+					Range: &result.Range{
+						Start: result.Position{Line: 8},
+						End:   result.Position{Line: 8},
+					},
+					SchemaPointer: "/properties/testConfig",
+				},
+			},
+			want: `
+{
+	"required": ["testConfig", "testString"],
+	"type": "object",
+	"properties": {
+		"testString": {
+			"title": "testString",
+			"type": "string",
+			"default": "foo"
+		},
+		"testConfig": {
+			"title": "testConfig",
+			"description": "a parameter with a user defined type",
+			"$comment": "Airlock Warning: unknown type from Bicep parameter (config)"
+		}
+	}
+}
 `,
 		},
 	}
@@ -9,12 +9,24 @@ import (
 	"reflect"
 	"sort"
 
+	"github.com/massdriver-cloud/airlock/pkg/result"
 	"github.com/massdriver-cloud/airlock/pkg/schema"
 )
 
 var indent = "  "
 
+// SchemaToBicep converts a JSON schema to Bicep parameters
 func SchemaToBicep(in io.Reader) ([]byte, error) {
+	converted, err := SchemaToBicepWithDiagnostics(in)
+	if err != nil {
+		return nil, err
+	}
+	return converted.Code, nil
+}
+
+// SchemaToBicepWithDiagnostics converts a JSON schema to Bicep parameters, along with the recursive references left
+// unresolved and the conflicts found merging allOf/anyOf/oneOf and conditional branches
+func SchemaToBicepWithDiagnostics(in io.Reader) (*result.CodeResult, error) {
 	inBytes, err := io.ReadAll(in)
 	if err != nil {
 		return nil, err
@@ -26,9 +38,15 @@ func SchemaToBicep(in io.Reader) ([]byte, error) {
 		return nil, err
 	}
 
+	recursiveRefs, err := schema.Resolve(&root, schema.SourcePath(in))
+	if err != nil {
+		return nil, err
+	}
+
 	content := bytes.NewBuffer(nil)
 
-	flattenedProperties := schema.ExpandProperties(&root)
+	flattened, conflicts := schema.Flatten(&root)
+	flattenedProperties := schema.ExpandProperties(flattened)
 	for prop := flattenedProperties.Oldest(); prop != nil; prop = prop.Next() {
 		err = createBicepParameter(prop.Key, prop.Value, content)
 		if err != nil {
@@ -36,11 +54,15 @@ func SchemaToBicep(in io.Reader) ([]byte, error) {
 		}
 	}
 
-	return content.Bytes(), nil
+	return &result.CodeResult{
+		Code:  content.Bytes(),
+		Diags: append(result.RecursiveRefDiagnostics(recursiveRefs), result.ConflictDiagnostics(conflicts)...),
+	}, nil
 }
 
 func createBicepParameter(name string, sch *schema.Schema, buf *bytes.Buffer) error {
-	bicepType, err := getBicepTypeFromSchema(sch.Type)
+	schemaType, _ := sch.NullableType()
+	bicepType, err := getBicepTypeFromSchema(schemaType)
 	if err != nil {
 		return err
 	}
@@ -69,6 +91,11 @@ func writeBicepParam(name string, sch *schema.Schema, buf *bytes.Buffer, bicepTy
 		defVal = fmt.Sprintf(" = %s", renderedVal)
 	}
 
+	// a type of [T, "null"] is a nullable T
+	if _, nullable := sch.NullableType(); nullable {
+		bicepType += "?"
+	}
+
 	fmt.Fprintf(buf, "param %s %s%s\n", name, bicepType, defVal)
 	return nil
 }
@@ -16,6 +16,12 @@ func TestSchemaToBicep(t *testing.T) {
 		{
 			name: "simple",
 		},
+		{
+			name: "refs",
+		},
+		{
+			name: "nullable",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,168 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/massdriver-cloud/airlock/pkg/schema/draft"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Check compares the schema generated from a module's variables to the contract schema the module has to satisfy.
+// A module satisfies the contract if every document that's valid against the contract can be passed to it, and
+// the module's defaults are valid against the contract. Paths are the dotted names of the variables, schema
+// pointers point into the contract, except for variables the contract doesn't have, which point into the module
+// schema
+func Check(contract, module *schema.Schema) []result.Diagnostic {
+	c := checker{diags: []result.Diagnostic{}}
+	c.compare("", "", contract, module)
+	return c.diags
+}
+
+type checker struct {
+	diags []result.Diagnostic
+}
+
+func (c *checker) add(path, pointer, code string, level result.Severity, format string, args ...any) {
+	c.diags = append(c.diags, result.Diagnostic{
+		Path:          path,
+		Code:          code,
+		Message:       fmt.Sprintf(format, args...),
+		Level:         level,
+		SchemaPointer: pointer,
+	})
+}
+
+// Returns false if the types don't match, in which case there's no point comparing anything else
+func (c *checker) compare(path, pointer string, contract, module *schema.Schema) bool {
+	if contract.Boolean != nil || module.Boolean != nil {
+		return true
+	}
+
+	if len(missingTypes(contract, module)) > 0 {
+		c.add(path, pointer, "type_mismatch", result.Error, "%s is %s in the contract but %s in the module", name(path), typeString(contract), typeString(module))
+		return false
+	}
+
+	if contract.Properties != nil && module.Properties != nil {
+		c.compareProperties(path, pointer, contract, module)
+	}
+	if contract.Items != nil && module.Items != nil {
+		c.compare(path+"[]", schema.AppendPointer(pointer, "items"), contract.Items, module.Items)
+	}
+	return true
+}
+
+func (c *checker) compareProperties(path, pointer string, contract, module *schema.Schema) {
+	for contractPair := contract.Properties.Oldest(); contractPair != nil; contractPair = contractPair.Next() {
+		propPath := propertyPath(path, contractPair.Key)
+		propPointer := schema.AppendPointer(pointer, "properties", contractPair.Key)
+
+		moduleProp, exists := module.Properties.Get(contractPair.Key)
+		if !exists {
+			c.add(propPath, propPointer, "missing_variable", result.Error, "%s is in the contract but not in the module", propPath)
+			continue
+		}
+
+		if requiresValue(module, contractPair.Key, moduleProp) && !slices.Contains(contract.Required, contractPair.Key) {
+			c.add(propPath, propPointer, "required_mismatch", result.Error, "%s is optional in the contract but the module requires it", propPath)
+		}
+		if c.compare(propPath, propPointer, contractPair.Value, moduleProp) && moduleProp.Default != nil {
+			c.checkDefault(propPath, propPointer, contractPair.Value, moduleProp.Default)
+		}
+	}
+
+	for modulePair := module.Properties.Oldest(); modulePair != nil; modulePair = modulePair.Next() {
+		if _, exists := contract.Properties.Get(modulePair.Key); exists {
+			continue
+		}
+		propPath := propertyPath(path, modulePair.Key)
+		// a variable with a default is harmless, callers following the contract just can't set it
+		level := result.Warning
+		if requiresValue(module, modulePair.Key, modulePair.Value) {
+			level = result.Error
+		}
+		c.add(propPath, schema.AppendPointer(pointer, "properties", modulePair.Key), "extra_variable", level, "%s is in the module but not in the contract", propPath)
+	}
+}
+
+func (c *checker) checkDefault(path, pointer string, contract *schema.Schema, value any) {
+	schemaBytes, err := json.Marshal(contract)
+	if err != nil {
+		return
+	}
+	// gojsonschema only knows draft-07, so tuples (prefixItems) have to be rewritten for it
+	schemaBytes, err = draft.Convert(schemaBytes, draft.Draft7)
+	if err != nil {
+		return
+	}
+	defaultBytes, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	// contracts gojsonschema can't compile are left for validation to report
+	validation, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaBytes), gojsonschema.NewBytesLoader(defaultBytes))
+	if err != nil || validation.Valid() {
+		return
+	}
+	for _, violation := range validation.Errors() {
+		c.add(path, pointer, "default_violates_contract", result.Error, "default %s of %s is invalid against the contract: %s", defaultBytes, path, violation)
+	}
+}
+
+// The types the contract allows but the module doesn't. Schemas without a type allow anything, so they're only
+// compared when both have one
+func missingTypes(contract, module *schema.Schema) []string {
+	contractTypes, moduleTypes := contract.TypeNames(), module.TypeNames()
+	if len(contractTypes) == 0 || len(moduleTypes) == 0 {
+		return nil
+	}
+	return slices.DeleteFunc(slices.Clone(contractTypes), func(t string) bool {
+		// every integer is a number too
+		return slices.Contains(moduleTypes, t) || (t == "integer" && slices.Contains(moduleTypes, "number"))
+	})
+}
+
+// A variable needs a value from the caller if the module requires it and has nothing to fall back on. Objects
+// have a default if every property in them does, the way Helm values do
+func requiresValue(parent *schema.Schema, key string, prop *schema.Schema) bool {
+	return slices.Contains(parent.Required, key) && !hasDefault(prop)
+}
+
+func hasDefault(sch *schema.Schema) bool {
+	if sch.Default != nil {
+		return true
+	}
+	if sch.Properties == nil || sch.Properties.Len() == 0 {
+		return false
+	}
+	for pair := sch.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		if !hasDefault(pair.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func propertyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func name(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+func typeString(sch *schema.Schema) string {
+	return strings.Join(sch.TypeNames(), " or ")
+}
@@ -0,0 +1,90 @@
+package contract_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/contract"
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	type testData struct {
+		name     string
+		contract string
+		module   string
+		want     []result.Diagnostic
+	}
+	tests := []testData{
+		{
+			name:     "satisfied",
+			contract: `{"required": ["name"], "properties": {"name": {"type": "string"}, "size": {"type": "integer", "minimum": 1}}}`,
+			module:   `{"required": ["name", "size"], "properties": {"name": {"type": "string"}, "size": {"type": "number", "default": 2}}}`,
+			want:     []result.Diagnostic{},
+		},
+		{
+			name:     "missing variable",
+			contract: `{"properties": {"name": {"type": "string"}, "tags": {"type": "object", "properties": {"team": {"type": "string"}}}}}`,
+			module:   `{"properties": {"tags": {"type": "object", "properties": {}}}}`,
+			want: []result.Diagnostic{
+				{Path: "name", Code: "missing_variable", Message: "name is in the contract but not in the module", Level: result.Error, SchemaPointer: "/properties/name"},
+				{Path: "tags.team", Code: "missing_variable", Message: "tags.team is in the contract but not in the module", Level: result.Error, SchemaPointer: "/properties/tags/properties/team"},
+			},
+		},
+		{
+			name:     "type mismatch",
+			contract: `{"properties": {"size": {"type": "number"}, "zones": {"type": "array", "items": {"type": "string"}}, "name": {"type": ["string", "null"]}}}`,
+			module:   `{"properties": {"size": {"type": "integer"}, "zones": {"type": "array", "items": {"type": "number"}}, "name": {"type": ["string", "null"]}}}`,
+			want: []result.Diagnostic{
+				{Path: "size", Code: "type_mismatch", Message: "size is number in the contract but integer in the module", Level: result.Error, SchemaPointer: "/properties/size"},
+				{Path: "zones[]", Code: "type_mismatch", Message: "zones[] is string in the contract but number in the module", Level: result.Error, SchemaPointer: "/properties/zones/items"},
+			},
+		},
+		{
+			name:     "default violates contract",
+			contract: `{"properties": {"size": {"type": "integer", "maximum": 10}, "settings": {"type": "object", "properties": {"mode": {"enum": ["fast", "slow"]}}}}}`,
+			module:   `{"properties": {"size": {"type": "integer", "default": 20}, "settings": {"type": "object", "properties": {"mode": {"type": "string", "default": "medium"}}}}}`,
+			want: []result.Diagnostic{
+				{Path: "size", Code: "default_violates_contract", Message: "default 20 of size is invalid against the contract: (root): Must be less than or equal to 10", Level: result.Error, SchemaPointer: "/properties/size"},
+				{Path: "settings.mode", Code: "default_violates_contract", Message: `default "medium" of settings.mode is invalid against the contract: (root): (root) must be one of the following: "fast", "slow"`, Level: result.Error, SchemaPointer: "/properties/settings/properties/mode"},
+			},
+		},
+		{
+			name:     "tuple default",
+			contract: `{"properties": {"endpoint": {"type": "array", "prefixItems": [{"type": "string"}, {"type": "integer"}], "items": false}}}`,
+			module:   `{"properties": {"endpoint": {"type": "array", "prefixItems": [{"type": "string"}, {"type": "string"}], "items": false, "default": ["localhost", "443"]}}}`,
+			want: []result.Diagnostic{
+				{Path: "endpoint", Code: "default_violates_contract", Message: `default ["localhost","443"] of endpoint is invalid against the contract: 1: Invalid type. Expected: integer, given: string`, Level: result.Error, SchemaPointer: "/properties/endpoint"},
+			},
+		},
+		{
+			name:     "extra variables",
+			contract: `{"properties": {"name": {"type": "string"}}}`,
+			module:   `{"required": ["name", "region", "replicas", "labels"], "properties": {"name": {"type": "string", "default": "app"}, "region": {"type": "string"}, "replicas": {"type": "integer", "default": 1}, "labels": {"type": "object", "properties": {"team": {"type": "string", "default": "core"}}}}}`,
+			want: []result.Diagnostic{
+				{Path: "region", Code: "extra_variable", Message: "region is in the module but not in the contract", Level: result.Error, SchemaPointer: "/properties/region"},
+				{Path: "replicas", Code: "extra_variable", Message: "replicas is in the module but not in the contract", Level: result.Warning, SchemaPointer: "/properties/replicas"},
+				{Path: "labels", Code: "extra_variable", Message: "labels is in the module but not in the contract", Level: result.Warning, SchemaPointer: "/properties/labels"},
+			},
+		},
+		{
+			name:     "required mismatch",
+			contract: `{"required": ["name"], "properties": {"name": {"type": "string"}, "region": {"type": "string"}}}`,
+			module:   `{"required": ["name", "region"], "properties": {"name": {"type": "string"}, "region": {"type": "string"}}}`,
+			want: []result.Diagnostic{
+				{Path: "region", Code: "required_mismatch", Message: "region is optional in the contract but the module requires it", Level: result.Error, SchemaPointer: "/properties/region"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var contractSchema, moduleSchema schema.Schema
+			require.NoError(t, json.Unmarshal([]byte(tc.contract), &contractSchema))
+			require.NoError(t, json.Unmarshal([]byte(tc.module), &moduleSchema))
+
+			require.Equal(t, tc.want, contract.Check(&contractSchema, &moduleSchema))
+		})
+	}
+}
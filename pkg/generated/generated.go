@@ -0,0 +1,81 @@
+package generated
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const checksumPrefix = "airlock-checksum:"
+
+// Marker marks a file as generated by airlock. It has to be put in a comment (or $comment for a schema), then the
+// file has to be signed with Sign
+const Marker = "Code generated by airlock. DO NOT EDIT. " + checksumPrefix
+
+var checksumPattern = regexp.MustCompile(checksumPrefix + `([0-9a-f]{64})?`)
+
+var markerPattern = regexp.MustCompile(`\n?` + regexp.QuoteMeta(Marker) + `([0-9a-f]{64})?`)
+
+type Status int
+
+const (
+	// Unmarked files weren't generated by airlock
+	Unmarked Status = iota
+	// Modified files were generated by airlock and then edited
+	Modified
+	// Unmodified files are exactly as airlock generated them
+	Unmodified
+)
+
+// Sign fills in the checksum after the Marker in the content, so it can be told apart from hand-edited files later
+func Sign(content []byte) []byte {
+	checksum := sha256.Sum256(content)
+	return bytes.Replace(content, []byte(checksumPrefix), []byte(checksumPrefix+hex.EncodeToString(checksum[:])), 1)
+}
+
+// Unmark removes the Marker (signed or not) from a comment, along with the newline before it, leaving the comment
+// as it was before it was marked
+func Unmark(comment string) string {
+	return markerPattern.ReplaceAllString(comment, "")
+}
+
+// GetStatus checks whether the content was generated by airlock, and if so whether it's been edited since
+func GetStatus(content []byte) Status {
+	match := checksumPattern.FindSubmatchIndex(content)
+	if match == nil || match[2] < 0 {
+		return Unmarked
+	}
+
+	unsigned := append(bytes.Clone(content[:match[2]]), content[match[3]:]...)
+	checksum := sha256.Sum256(unsigned)
+	if hex.EncodeToString(checksum[:]) != string(content[match[2]:match[3]]) {
+		return Modified
+	}
+	return Unmodified
+}
+
+// WriteFile writes the content to a temporary file next to the path and renames it into place, so the file is never
+// left half written
+func WriteFile(path string, content []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	// a no-op once the file has been renamed
+	defer os.Remove(tmp.Name())
+
+	if _, writeErr := tmp.Write(content); writeErr != nil {
+		tmp.Close()
+		return writeErr
+	}
+	if closeErr := tmp.Close(); closeErr != nil {
+		return closeErr
+	}
+	if chmodErr := os.Chmod(tmp.Name(), perm); chmodErr != nil {
+		return chmodErr
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,73 @@
+package generated_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/generated"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatus(t *testing.T) {
+	signed := string(generated.Sign([]byte("# " + generated.Marker + "\n\nvariable \"foo\" {\n  type = string\n}\n")))
+
+	type testData struct {
+		name    string
+		content string
+		want    generated.Status
+	}
+	tests := []testData{
+		{
+			name:    "unmodified",
+			content: signed,
+			want:    generated.Unmodified,
+		},
+		{
+			name:    "modified",
+			content: strings.Replace(signed, "string", "number", 1),
+			want:    generated.Modified,
+		},
+		{
+			name:    "unsigned",
+			content: "# " + generated.Marker + "\n",
+			want:    generated.Unmarked,
+		},
+		{
+			name:    "unmarked",
+			content: "variable \"foo\" {}\n",
+			want:    generated.Unmarked,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, generated.GetStatus([]byte(tc.content)))
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.json")
+
+	require.NoError(t, generated.WriteFile(path, []byte("{}\n"), 0o644))
+	require.NoError(t, generated.WriteFile(path, []byte("{\"type\": \"object\"}\n"), 0o644))
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "{\"type\": \"object\"}\n", string(got))
+
+	// the temporary files are cleaned up
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestUnmark(t *testing.T) {
+	signed := string(generated.Sign([]byte("Settings for the app\n" + generated.Marker)))
+
+	require.Equal(t, "Settings for the app", generated.Unmark(signed))
+	require.Equal(t, "", generated.Unmark(generated.Marker))
+	require.Equal(t, "Settings for the app", generated.Unmark("Settings for the app"))
+}
@@ -23,6 +23,7 @@ func HelmToSchema(valuesPath string) result.SchemaResult {
 					Code:    "file_read_error",
 					Message: fmt.Sprintf("failed to read values file: %s", readErr),
 					Level:   result.Error,
+					File:    valuesPath,
 				},
 			},
 		}
@@ -40,6 +41,7 @@ func HelmToSchema(valuesPath string) result.SchemaResult {
 					Code:    "yaml_unmarshal_error",
 					Message: fmt.Sprintf("failed to unmarshal values file: %s", unmarshalErr),
 					Level:   result.Error,
+					File:    valuesPath,
 				},
 			},
 		}
@@ -47,66 +49,108 @@ func HelmToSchema(valuesPath string) result.SchemaResult {
 
 	sch := new(schema.Schema)
 	result := result.SchemaResult{
-		Schema: sch,
-		Diags:  []result.Diagnostic{},
+		Schema:    sch,
+		Diags:     []result.Diagnostic{},
+		Locations: map[string]result.Location{},
 	}
 
 	// the top level node is a document node. We need to go one layer
 	// deeper to get the actual yaml content
 	if len(valuesDocument.Content) > 0 {
-		result.Diags = parseMapNode(sch, valuesDocument.Content[0], result.Diags)
+		lines := strings.Split(string(valuesBytes), "\n")
+		result.Diags = parseMapNode(lines, sch, valuesDocument.Content[0], "", result.Diags)
+		valueLocations(lines, valuesDocument.Content[0], "", valuesPath, result.Locations)
+	}
+
+	for index := range result.Diags {
+		result.Diags[index].File = valuesPath
 	}
 
 	return result
 }
 
+// Record where each value is declared, keyed by the pointer of its property. Arrays are typed from their first item,
+// so that's where their items are declared
+func valueLocations(lines []string, node *yaml.Node, pointer, valuesPath string, locations map[string]result.Location) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			nameNode := node.Content[index]
+			propertyPointer := schema.AppendPointer(pointer, "properties", nameNode.Value)
+			locations[propertyPointer] = result.Location{File: valuesPath, Range: result.YAMLRange(lines, nameNode)}
+			valueLocations(lines, node.Content[index+1], propertyPointer, valuesPath, locations)
+		}
+	case yaml.SequenceNode:
+		if len(node.Content) > 0 {
+			itemsPointer := schema.AppendPointer(pointer, "items")
+			locations[itemsPointer] = result.Location{File: valuesPath, Range: result.YAMLRange(lines, node.Content[0])}
+			valueLocations(lines, node.Content[0], itemsPointer, valuesPath, locations)
+		}
+	}
+}
+
 func parseNameNode(schema *schema.Schema, node *yaml.Node) {
 	schema.Title = node.Value
 
-	description := strings.TrimLeft(node.HeadComment, "# \t")
+	description := strings.TrimLeft(withoutIgnoreDirectives(node.HeadComment), "# \t")
 	if len(description) > 0 {
 		schema.Description = description
 	}
 }
 
-func parseValueNode(schema *schema.Schema, node *yaml.Node, diags []result.Diagnostic) []result.Diagnostic {
+// Comments that suppress diagnostics aren't part of the description
+func withoutIgnoreDirectives(comment string) string {
+	lines := []string{}
+	for _, line := range strings.Split(comment, "\n") {
+		if !strings.Contains(line, result.IgnoreDirective) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseValueNode(lines []string, schema *schema.Schema, node *yaml.Node, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	switch node.Tag {
 	case "!!str":
 		parseStringNode(schema, node)
 	case "!!int":
-		return parseIntegerNode(schema, node, diags)
+		return parseIntegerNode(lines, schema, node, pointer, diags)
 	case "!!float":
-		return parseFloatNode(schema, node, diags)
+		return parseFloatNode(lines, schema, node, pointer, diags)
 	case "!!bool":
-		return parseBooleanNode(schema, node, diags)
+		return parseBooleanNode(lines, schema, node, pointer, diags)
 	case "!!map":
-		return parseMapNode(schema, node, diags)
+		return parseMapNode(lines, schema, node, pointer, diags)
 	case "!!seq":
-		return parseArrayNode(schema, node, diags)
+		return parseArrayNode(lines, schema, node, pointer, diags)
 	case "!!null":
 		schema.Comment = "Airlock Warning: unknown type from null value"
 		return append(diags, result.Diagnostic{
-			Path:    schema.Title,
-			Code:    "unknown_type",
-			Message: fmt.Sprintf("type of field %s is indeterminate (null)", schema.Title),
-			Level:   result.Warning,
+			Path:          schema.Title,
+			Code:          "unknown_type",
+			Message:       fmt.Sprintf("type of field %s is indeterminate (null)", schema.Title),
+			Level:         result.Warning,
+			Range:         result.YAMLRange(lines, node),
+			SchemaPointer: pointer,
 		})
 	default:
 		schema.Comment = fmt.Sprintf("Airlock Warning: unknown type %s", node.Tag)
 		return append(diags, result.Diagnostic{
-			Path:    schema.Title,
-			Code:    "unknown_type",
-			Message: fmt.Sprintf("type of field %s is unsupported (%s)", schema.Title, node.Tag),
-			Level:   result.Warning,
+			Path:          schema.Title,
+			Code:          "unknown_type",
+			Message:       fmt.Sprintf("type of field %s is unsupported (%s)", schema.Title, node.Tag),
+			Level:         result.Warning,
+			Range:         result.YAMLRange(lines, node),
+			SchemaPointer: pointer,
 		})
 	}
 	return diags
 }
 
-func nodeToProperty(sch *schema.Schema, name, value *yaml.Node, diags []result.Diagnostic) []result.Diagnostic {
+func nodeToProperty(lines []string, sch *schema.Schema, name, value *yaml.Node, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	parseNameNode(sch, name)
 
-	diags = parseValueNode(sch, value, diags)
+	diags = parseValueNode(lines, sch, value, pointer, diags)
 
 	return diags
 }
@@ -116,52 +160,58 @@ func parseStringNode(sch *schema.Schema, node *yaml.Node) {
 	sch.Default = node.Value
 }
 
-func parseIntegerNode(sch *schema.Schema, node *yaml.Node, diags []result.Diagnostic) []result.Diagnostic {
+func parseIntegerNode(lines []string, sch *schema.Schema, node *yaml.Node, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "integer"
 	def, err := strconv.Atoi(node.Value)
 	if err != nil {
 		return append(diags, result.Diagnostic{
-			Path:    node.Value,
-			Code:    "invalid_value",
-			Message: fmt.Sprintf("failed to parse integer: %s", err),
-			Level:   result.Error,
+			Path:          sch.Title,
+			Code:          "invalid_value",
+			Message:       fmt.Sprintf("failed to parse integer: %s", err),
+			Level:         result.Error,
+			Range:         result.YAMLRange(lines, node),
+			SchemaPointer: pointer,
 		})
 	}
 	sch.Default = def
 	return diags
 }
 
-func parseFloatNode(sch *schema.Schema, node *yaml.Node, diags []result.Diagnostic) []result.Diagnostic {
+func parseFloatNode(lines []string, sch *schema.Schema, node *yaml.Node, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "number"
 	def, err := strconv.ParseFloat(node.Value, 64)
 	if err != nil {
 		return append(diags, result.Diagnostic{
-			Path:    node.Value,
-			Code:    "invalid_value",
-			Message: fmt.Sprintf("failed to parse float: %s", err),
-			Level:   result.Error,
+			Path:          sch.Title,
+			Code:          "invalid_value",
+			Message:       fmt.Sprintf("failed to parse float: %s", err),
+			Level:         result.Error,
+			Range:         result.YAMLRange(lines, node),
+			SchemaPointer: pointer,
 		})
 	}
 	sch.Default = def
 	return diags
 }
 
-func parseBooleanNode(sch *schema.Schema, node *yaml.Node, diags []result.Diagnostic) []result.Diagnostic {
+func parseBooleanNode(lines []string, sch *schema.Schema, node *yaml.Node, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "boolean"
 	def, err := strconv.ParseBool(node.Value)
 	if err != nil {
 		return append(diags, result.Diagnostic{
-			Path:    node.Value,
-			Code:    "invalid_value",
-			Message: fmt.Sprintf("failed to parse boolean: %s", err),
-			Level:   result.Error,
+			Path:          sch.Title,
+			Code:          "invalid_value",
+			Message:       fmt.Sprintf("failed to parse boolean: %s", err),
+			Level:         result.Error,
+			Range:         result.YAMLRange(lines, node),
+			SchemaPointer: pointer,
 		})
 	}
 	sch.Default = def
 	return diags
 }
 
-func parseMapNode(sch *schema.Schema, node *yaml.Node, diags []result.Diagnostic) []result.Diagnostic {
+func parseMapNode(lines []string, sch *schema.Schema, node *yaml.Node, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "object"
 	sch.Properties = orderedmap.New[string, *schema.Schema]()
 
@@ -172,7 +222,7 @@ func parseMapNode(sch *schema.Schema, node *yaml.Node, diags []result.Diagnostic
 		valueNode := nodes[index+1]
 
 		property := new(schema.Schema)
-		diags = nodeToProperty(property, nameNode, valueNode, diags)
+		diags = nodeToProperty(lines, property, nameNode, valueNode, schema.AppendPointer(pointer, "properties", nameNode.Value), diags)
 
 		sch.Properties.Set(nameNode.Value, property)
 		sch.Required = append(sch.Required, nameNode.Value)
@@ -181,7 +231,7 @@ func parseMapNode(sch *schema.Schema, node *yaml.Node, diags []result.Diagnostic
 	return diags
 }
 
-func parseArrayNode(sch *schema.Schema, node *yaml.Node, diags []result.Diagnostic) []result.Diagnostic {
+func parseArrayNode(lines []string, sch *schema.Schema, node *yaml.Node, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "array"
 
 	sch.Items = new(schema.Schema)
@@ -189,23 +239,27 @@ func parseArrayNode(sch *schema.Schema, node *yaml.Node, diags []result.Diagnost
 	if len(node.Content) == 0 {
 		sch.Items.Comment = "Airlock Warning: unknown type from empty array"
 		return append(diags, result.Diagnostic{
-			Path:    sch.Title,
-			Code:    "unknown_type",
-			Message: fmt.Sprintf("array %s is empty so it's type is unknown", sch.Title),
-			Level:   result.Warning,
+			Path:          sch.Title,
+			Code:          "unknown_type",
+			Message:       fmt.Sprintf("array %s is empty so it's type is unknown", sch.Title),
+			Level:         result.Warning,
+			Range:         result.YAMLRange(lines, node),
+			SchemaPointer: pointer,
 		})
 	}
 
-	diags = parseValueNode(sch.Items, node.Content[0], diags)
+	diags = parseValueNode(lines, sch.Items, node.Content[0], schema.AppendPointer(pointer, "items"), diags)
 
 	// Set the default back to nil since we don't want to default all items to the first type in the list
 	decodeErr := node.Decode(&sch.Default)
 	if decodeErr != nil {
 		return append(diags, result.Diagnostic{
-			Path:    sch.Title,
-			Code:    "invalid_type",
-			Message: fmt.Sprintf("failed to decode array default: %s", decodeErr),
-			Level:   result.Error,
+			Path:          sch.Title,
+			Code:          "invalid_type",
+			Message:       fmt.Sprintf("failed to decode array default: %s", decodeErr),
+			Level:         result.Error,
+			Range:         result.YAMLRange(lines, node),
+			SchemaPointer: pointer,
 		})
 	}
 
@@ -27,12 +27,24 @@ func TestRun(t *testing.T) {
 					Code:    "unknown_type",
 					Message: "array emptyArray is empty so it's type is unknown",
 					Level:   result.Warning,
+					File:    "testdata/values.yaml",
+					Range: &result.Range{
+						Start: result.Position{Line: 22, Column: 13},
+						End:   result.Position{Line: 22, Column: 13},
+					},
+					SchemaPointer: "/properties/emptyArray",
 				},
 				{
 					Path:    "nullValue",
 					Code:    "unknown_type",
 					Message: "type of field nullValue is indeterminate (null)",
 					Level:   result.Warning,
+					File:    "testdata/values.yaml",
+					Range: &result.Range{
+						Start: result.Position{Line: 24, Column: 11},
+						End:   result.Position{Line: 24, Column: 11},
+					},
+					SchemaPointer: "/properties/nullValue",
 				},
 			},
 			want: `
@@ -117,6 +129,51 @@ func TestRun(t *testing.T) {
 }
 `,
 		},
+		{
+			name:       "ignore directive",
+			valuesPath: "testdata/ignore.yaml",
+			diags: []result.Diagnostic{
+				{
+					Path:    "logLevel",
+					Code:    "unknown_type",
+					Message: "type of field logLevel is indeterminate (null)",
+					Level:   result.Warning,
+					File:    "testdata/ignore.yaml",
+					Range: &result.Range{
+						Start: result.Position{Line: 3, Column: 10},
+						End:   result.Position{Line: 3, Column: 10},
+					},
+					SchemaPointer: "/properties/logLevel",
+				},
+			},
+			want: `
+{
+	"required": ["logLevel"],
+	"type": "object",
+	"properties": {
+		"logLevel": {
+			"title": "logLevel",
+			"description": "The log level",
+			"$comment": "Airlock Warning: unknown type from null value"
+		}
+	}
+}
+`,
+		},
+		{
+			name:       "missing file",
+			valuesPath: "testdata/missing.yaml",
+			diags: []result.Diagnostic{
+				{
+					Path:    "testdata/missing.yaml",
+					Code:    "file_read_error",
+					Message: "failed to read values file: open testdata/missing.yaml: no such file or directory",
+					Level:   result.Error,
+					File:    "testdata/missing.yaml",
+				},
+			},
+			want: `null`,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
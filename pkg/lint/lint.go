@@ -0,0 +1,86 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+)
+
+// Config selects the rules to run. If Enable is set only those rules run, otherwise every rule runs. Disabled rules
+// never run
+type Config struct {
+	Enable  []string
+	Disable []string
+}
+
+// Lint a schema, returning a diagnostic for every finding of the configured rules. Paths are JSON pointers to the
+// offending schema
+func Lint(sch *schema.Schema, config Config) ([]result.Diagnostic, error) {
+	rules, err := config.rules()
+	if err != nil {
+		return nil, err
+	}
+
+	diags := []result.Diagnostic{}
+	_, err = schema.Walk(sch, schema.Visitor{
+		Pre: func(path string, node *schema.Schema) (*schema.Schema, error) {
+			for _, rule := range rules {
+				for _, message := range rule.check(path, node) {
+					diags = append(diags, result.Diagnostic{
+						Path:          path,
+						Code:          rule.Code,
+						Message:       message,
+						Level:         rule.Level,
+						SchemaPointer: path,
+					})
+				}
+			}
+			return node, nil
+		},
+	})
+	return diags, err
+}
+
+func (config Config) rules() ([]Rule, error) {
+	for _, code := range slices.Concat(config.Enable, config.Disable) {
+		if !slices.ContainsFunc(Rules, func(rule Rule) bool { return rule.Code == code }) {
+			return nil, fmt.Errorf("unknown rule %q", code)
+		}
+	}
+
+	rules := []Rule{}
+	for _, rule := range Rules {
+		if len(config.Enable) > 0 && !slices.Contains(config.Enable, rule.Code) {
+			continue
+		}
+		if slices.Contains(config.Disable, rule.Code) {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// keywords that are followed by a property name or index in a path
+var indexedKeywords = []string{
+	"$defs", "definitions", "dependencies", "dependentSchemas", "patternProperties", "properties",
+	"allOf", "anyOf", "oneOf", "prefixItems",
+}
+
+// The keyword a schema is under, "properties" for /properties/foo and "items" for /properties/foo/items. The path
+// is read from the start since property names can be keywords too
+func location(path string) string {
+	segments := strings.Split(path, "/")[1:]
+	keyword := ""
+	for i := 0; i < len(segments); i++ {
+		keyword = segments[i]
+		if slices.Contains(indexedKeywords, keyword) {
+			// skip the name or index
+			i++
+		}
+	}
+	return keyword
+}
@@ -0,0 +1,103 @@
+package lint_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/lint"
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	type testData struct {
+		name   string
+		schema string
+		config lint.Config
+		want   []result.Diagnostic
+	}
+	tests := []testData{
+		{
+			name:   "clean",
+			schema: `{"required": ["name"], "properties": {"name": {"title": "Name", "description": "The name", "type": "string", "default": "foo"}}}`,
+			want:   []result.Diagnostic{},
+		},
+		{
+			name:   "missing title and description",
+			schema: `{"properties": {"name": {"type": "string"}, "properties": {"title": "Properties", "description": "Named like a keyword", "type": "object", "properties": {"items": {"type": "string", "description": "Nested"}}}}}`,
+			want: []result.Diagnostic{
+				{Path: "/properties/name", Code: "missing_title", Message: "property has no title", Level: result.Warning, SchemaPointer: "/properties/name"},
+				{Path: "/properties/name", Code: "missing_description", Message: "property has no description", Level: result.Warning, SchemaPointer: "/properties/name"},
+				{Path: "/properties/properties/properties/items", Code: "missing_title", Message: "property has no title", Level: result.Warning, SchemaPointer: "/properties/properties/properties/items"},
+			},
+		},
+		{
+			name:   "required not in properties",
+			schema: `{"required": ["name", "size"], "properties": {"name": {"type": "string"}}, "if": {"properties": {"name": {"const": "db"}}}, "then": {"required": ["size"]}}`,
+			config: lint.Config{Enable: []string{"required_not_in_properties"}},
+			want: []result.Diagnostic{
+				{Path: "", Code: "required_not_in_properties", Message: "required property 'size' isn't in properties", Level: result.Error, SchemaPointer: ""},
+			},
+		},
+		{
+			name:   "invalid default",
+			schema: `{"properties": {"port": {"type": "integer", "maximum": 100, "default": 8080}, "tags": {"type": "array", "items": {"type": "string"}, "default": ["a", "b"]}, "address": {"type": "array", "prefixItems": [{"type": "string"}, {"type": "number"}], "items": false, "default": ["localhost", 443]}, "listener": {"type": "array", "prefixItems": [{"type": "string"}, {"type": "number"}], "items": false, "default": ["localhost", "443"]}}}`,
+			config: lint.Config{Enable: []string{"invalid_default"}},
+			want: []result.Diagnostic{
+				{Path: "/properties/port", Code: "invalid_default", Message: "default 8080 is invalid: (root): Must be less than or equal to 100", Level: result.Error, SchemaPointer: "/properties/port"},
+				{Path: "/properties/listener", Code: "invalid_default", Message: `default ["localhost","443"] is invalid: 1: Invalid type. Expected: number, given: string`, Level: result.Error, SchemaPointer: "/properties/listener"},
+			},
+		},
+		{
+			name:   "mixed enum types",
+			schema: `{"properties": {"size": {"enum": ["small", 1, true]}, "nullable": {"enum": ["small", null]}}}`,
+			config: lint.Config{Enable: []string{"mixed_enum_types"}},
+			want: []result.Diagnostic{
+				{Path: "/properties/size", Code: "mixed_enum_types", Message: "enum has values of types string, number, boolean", Level: result.Warning, SchemaPointer: "/properties/size"},
+			},
+		},
+		{
+			name:   "unconstrained type",
+			schema: `{"properties": {"anything": {"title": "Anything"}, "list": {"type": "array", "items": {}}, "choice": {"oneOf": [{"type": "string"}, {"type": "number"}]}}, "then": {"required": ["list"]}}`,
+			config: lint.Config{Enable: []string{"unconstrained_type"}},
+			want: []result.Diagnostic{
+				{Path: "/properties/anything", Code: "unconstrained_type", Message: "unconstrained type, any value is allowed", Level: result.Warning, SchemaPointer: "/properties/anything"},
+				{Path: "/properties/list/items", Code: "unconstrained_type", Message: "unconstrained type, any value is allowed", Level: result.Warning, SchemaPointer: "/properties/list/items"},
+			},
+		},
+		{
+			name:   "unreachable oneOf branches",
+			schema: `{"type": "string", "oneOf": [{"const": "a"}, false, {"const": "a"}, {"type": "number"}, {"type": "string", "maxLength": 1}]}`,
+			config: lint.Config{Enable: []string{"unreachable_oneof_branch"}},
+			want: []result.Diagnostic{
+				{Path: "", Code: "unreachable_oneof_branch", Message: "oneOf branch 1 is false", Level: result.Error, SchemaPointer: ""},
+				{Path: "", Code: "unreachable_oneof_branch", Message: "oneOf branch 2 duplicates branch 0, values matching both are rejected", Level: result.Error, SchemaPointer: ""},
+				{Path: "", Code: "unreachable_oneof_branch", Message: "oneOf branch 3 has type number which isn't allowed by type string", Level: result.Error, SchemaPointer: ""},
+			},
+		},
+		{
+			name:   "disabled rules",
+			schema: `{"properties": {"name": {}}}`,
+			config: lint.Config{Disable: []string{"missing_title", "missing_description"}},
+			want: []result.Diagnostic{
+				{Path: "/properties/name", Code: "unconstrained_type", Message: "unconstrained type, any value is allowed", Level: result.Warning, SchemaPointer: "/properties/name"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var sch schema.Schema
+			require.NoError(t, json.Unmarshal([]byte(tc.schema), &sch))
+
+			got, err := lint.Lint(&sch, tc.config)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestLintUnknownRule(t *testing.T) {
+	_, err := lint.Lint(new(schema.Schema), lint.Config{Disable: []string{"missing_titles"}})
+	require.EqualError(t, err, `unknown rule "missing_titles"`)
+}
@@ -0,0 +1,218 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/massdriver-cloud/airlock/pkg/schema/draft"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Rule checks every schema in a document, returning a message for each finding
+type Rule struct {
+	Code        string
+	Level       result.Severity
+	Description string
+	check       func(path string, node *schema.Schema) []string
+}
+
+// Rules is every lint rule, in the order they are run
+var Rules = []Rule{
+	{
+		Code:        "missing_title",
+		Level:       result.Warning,
+		Description: "Property has no title",
+		check:       checkMissingTitle,
+	},
+	{
+		Code:        "missing_description",
+		Level:       result.Warning,
+		Description: "Property has no description",
+		check:       checkMissingDescription,
+	},
+	{
+		Code:        "required_not_in_properties",
+		Level:       result.Error,
+		Description: "Required property isn't in properties",
+		check:       checkRequiredNotInProperties,
+	},
+	{
+		Code:        "invalid_default",
+		Level:       result.Error,
+		Description: "Default doesn't validate against its own schema",
+		check:       checkInvalidDefault,
+	},
+	{
+		Code:        "mixed_enum_types",
+		Level:       result.Warning,
+		Description: "Enum has values of more than one type",
+		check:       checkMixedEnumTypes,
+	},
+	{
+		Code:        "unconstrained_type",
+		Level:       result.Warning,
+		Description: "Property or item allows any type",
+		check:       checkUnconstrainedType,
+	},
+	{
+		Code:        "unreachable_oneof_branch",
+		Level:       result.Error,
+		Description: "oneOf branch can never match",
+		check:       checkUnreachableOneOf,
+	},
+}
+
+func checkMissingTitle(path string, node *schema.Schema) []string {
+	if location(path) == "properties" && node.Boolean == nil && node.Title == "" {
+		return []string{"property has no title"}
+	}
+	return nil
+}
+
+func checkMissingDescription(path string, node *schema.Schema) []string {
+	if location(path) == "properties" && node.Boolean == nil && node.Description == "" {
+		return []string{"property has no description"}
+	}
+	return nil
+}
+
+// Only checked when the schema has properties, a required list on its own (in a then or allOf) refers to the
+// properties of the parent
+func checkRequiredNotInProperties(_ string, node *schema.Schema) []string {
+	properties := schema.ExpandProperties(node)
+	if properties.Len() == 0 {
+		return nil
+	}
+	messages := []string{}
+	for _, name := range node.Required {
+		if _, exists := properties.Get(name); !exists {
+			messages = append(messages, fmt.Sprintf("required property '%s' isn't in properties", name))
+		}
+	}
+	return messages
+}
+
+func checkInvalidDefault(_ string, node *schema.Schema) []string {
+	if node.Default == nil {
+		return nil
+	}
+
+	withoutDefault := *node
+	withoutDefault.Default = nil
+	schemaBytes, err := json.Marshal(withoutDefault)
+	if err != nil {
+		return nil
+	}
+	// gojsonschema only knows draft-07, so tuples (prefixItems) have to be rewritten for it
+	schemaBytes, err = draft.Convert(schemaBytes, draft.Draft7)
+	if err != nil {
+		return nil
+	}
+	defaultBytes, err := json.Marshal(node.Default)
+	if err != nil {
+		return nil
+	}
+
+	// schemas gojsonschema can't compile are left for validation to report
+	validation, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaBytes), gojsonschema.NewBytesLoader(defaultBytes))
+	if err != nil || validation.Valid() {
+		return nil
+	}
+	messages := []string{}
+	for _, violation := range validation.Errors() {
+		messages = append(messages, fmt.Sprintf("default %s is invalid: %s", defaultBytes, violation))
+	}
+	return messages
+}
+
+func checkMixedEnumTypes(_ string, node *schema.Schema) []string {
+	types := []string{}
+	for _, value := range node.Enum {
+		// null is allowed alongside any type for nullable enums
+		t := jsonType(value)
+		if t != "null" && !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	if len(types) > 1 {
+		return []string{fmt.Sprintf("enum has values of types %s", strings.Join(types, ", "))}
+	}
+	return nil
+}
+
+// Only values (properties and items) are checked, schemas under allOf, if, then and the like usually only add
+// constraints to their parent
+func checkUnconstrainedType(path string, node *schema.Schema) []string {
+	switch location(path) {
+	case "properties", "patternProperties", "additionalProperties", "items", "prefixItems":
+	default:
+		return nil
+	}
+	constrained := node.Boolean != nil ||
+		len(node.TypeNames()) > 0 ||
+		len(node.Enum) > 0 ||
+		node.Const != nil ||
+		node.Properties != nil ||
+		node.Items != nil ||
+		len(node.AllOf) > 0 ||
+		len(node.AnyOf) > 0 ||
+		len(node.OneOf) > 0
+	if constrained {
+		return nil
+	}
+	return []string{"unconstrained type, any value is allowed"}
+}
+
+// A oneOf branch is unreachable if it's false, duplicates another branch (a value matching both fails oneOf) or
+// only allows types the parent doesn't
+func checkUnreachableOneOf(_ string, node *schema.Schema) []string {
+	messages := []string{}
+	for i, branch := range node.OneOf {
+		if branch.Boolean != nil && !*branch.Boolean {
+			messages = append(messages, fmt.Sprintf("oneOf branch %d is false", i))
+			continue
+		}
+
+		if duplicate := slices.IndexFunc(node.OneOf[:i], func(other *schema.Schema) bool {
+			return reflect.DeepEqual(other, branch)
+		}); duplicate >= 0 {
+			messages = append(messages, fmt.Sprintf("oneOf branch %d duplicates branch %d, values matching both are rejected", i, duplicate))
+			continue
+		}
+
+		parentTypes := node.TypeNames()
+		branchTypes := branch.TypeNames()
+		if len(parentTypes) > 0 && len(branchTypes) > 0 && !slices.ContainsFunc(branchTypes, func(t string) bool { return typeAllowed(t, parentTypes) }) {
+			messages = append(messages, fmt.Sprintf("oneOf branch %d has type %s which isn't allowed by type %s", i, strings.Join(branchTypes, "|"), strings.Join(parentTypes, "|")))
+		}
+	}
+	return messages
+}
+
+func typeAllowed(t string, types []string) bool {
+	return slices.Contains(types, t) ||
+		(t == "integer" && slices.Contains(types, "number")) ||
+		(t == "number" && slices.Contains(types, "integer"))
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64, json.Number, int:
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,137 @@
+package opentofu
+
+import (
+	"slices"
+	"strings"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/massdriver-cloud/terraform-config-inspect/tfconfig"
+)
+
+type options struct {
+	alphabetical     bool
+	implicitNullable bool
+}
+
+// Option configures how a module is converted into a schema
+type Option func(*options)
+
+// WithAlphabeticalOrder orders properties by name instead of the order they are declared in the module
+func WithAlphabeticalOrder() Option {
+	return func(o *options) {
+		o.alphabetical = true
+	}
+}
+
+// WithImplicitNullable makes variables accept null unless they set nullable = false, the way OpenTofu treats them.
+// By default only variables that set nullable = true do, since null is rarely a value callers mean to pass
+func WithImplicitNullable() Option {
+	return func(o *options) {
+		o.implicitNullable = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Variables are ordered by where they are declared in the module (file, then line), so output is stable between runs
+func sortedVariables(module *tfconfig.Module) []*tfconfig.Variable {
+	variables := make([]*tfconfig.Variable, 0, len(module.Variables))
+	for _, variable := range module.Variables {
+		variables = append(variables, variable)
+	}
+	slices.SortFunc(variables, func(a, b *tfconfig.Variable) int {
+		return compareSourcePos(a.Pos, b.Pos)
+	})
+	return variables
+}
+
+// Outputs are ordered the same way as variables
+func sortedOutputs(module *tfconfig.Module) []*tfconfig.Output {
+	outputs := make([]*tfconfig.Output, 0, len(module.Outputs))
+	for _, output := range module.Outputs {
+		outputs = append(outputs, output)
+	}
+	slices.SortFunc(outputs, func(a, b *tfconfig.Output) int {
+		return compareSourcePos(a.Pos, b.Pos)
+	})
+	return outputs
+}
+
+func compareSourcePos(a, b tfconfig.SourcePos) int {
+	if a.Filename != b.Filename {
+		return strings.Compare(a.Filename, b.Filename)
+	}
+	return a.Line - b.Line
+}
+
+// cty object types don't retain the order of their attributes, so walk the type expression alongside
+// the schema and reorder the object properties to match the order the attributes were declared in
+func orderPropertiesBySource(sch *schema.Schema, typeExpr hclsyntax.Expression) {
+	call, ok := typeExpr.(*hclsyntax.FunctionCallExpr)
+	if !ok || sch == nil || len(call.Args) == 0 {
+		return
+	}
+
+	switch call.Name {
+	case "optional":
+		orderPropertiesBySource(sch, call.Args[0])
+	case "list", "set":
+		orderPropertiesBySource(sch.Items, call.Args[0])
+	case "map":
+		if addProps, isSchema := sch.AdditionalProperties.(*schema.Schema); isSchema {
+			orderPropertiesBySource(addProps, call.Args[0])
+		}
+	case "tuple":
+		elements, isTuple := call.Args[0].(*hclsyntax.TupleConsExpr)
+		if !isTuple {
+			return
+		}
+		for index, element := range elements.Exprs {
+			if index < len(sch.PrefixItems) {
+				orderPropertiesBySource(sch.PrefixItems[index], element)
+			}
+		}
+	case "object":
+		attributes, isObject := call.Args[0].(*hclsyntax.ObjectConsExpr)
+		if !isObject || sch.Properties == nil {
+			return
+		}
+		for _, item := range attributes.Items {
+			name := hcl.ExprAsKeyword(item.KeyExpr)
+			property, exists := sch.Properties.Get(name)
+			if !exists {
+				continue
+			}
+			_ = sch.Properties.MoveToBack(name)
+			orderPropertiesBySource(property, item.ValueExpr)
+		}
+	}
+}
+
+// Recursively order all properties by name
+func orderPropertiesAlphabetically(sch *schema.Schema) {
+	_, _ = schema.Walk(sch, schema.Visitor{
+		Pre: func(_ string, node *schema.Schema) (*schema.Schema, error) {
+			if node.Properties == nil {
+				return node, nil
+			}
+			names := []string{}
+			for prop := node.Properties.Oldest(); prop != nil; prop = prop.Next() {
+				names = append(names, prop.Key)
+			}
+			slices.Sort(names)
+			for _, name := range names {
+				_ = node.Properties.MoveToBack(name)
+			}
+			return node, nil
+		},
+	})
+}
@@ -0,0 +1,288 @@
+package opentofu
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/massdriver-cloud/terraform-config-inspect/tfconfig"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// Functions whose return type doesn't depend on their arguments
+var functionReturnTypes = map[string]string{
+	"abs":          "number",
+	"alltrue":      "boolean",
+	"anytrue":      "boolean",
+	"base64decode": "string",
+	"base64encode": "string",
+	"can":          "boolean",
+	"ceil":         "number",
+	"chomp":        "string",
+	"cidrhost":     "string",
+	"cidrnetmask":  "string",
+	"cidrsubnet":   "string",
+	"contains":     "boolean",
+	"endswith":     "boolean",
+	"floor":        "number",
+	"format":       "string",
+	"join":         "string",
+	"jsonencode":   "string",
+	"length":       "number",
+	"lower":        "string",
+	"max":          "number",
+	"md5":          "string",
+	"min":          "number",
+	"parseint":     "number",
+	"replace":      "string",
+	"sha256":       "string",
+	"startswith":   "boolean",
+	"substr":       "string",
+	"title":        "string",
+	"tobool":       "boolean",
+	"tonumber":     "number",
+	"tostring":     "string",
+	"trimspace":    "string",
+	"upper":        "string",
+	"uuid":         "string",
+	"yamlencode":   "string",
+}
+
+// OutputsToSchema generates an object schema describing the outputs of a module. Output types are inferred
+// from their value expressions where possible (literals, variables, resource IDs, string templates, functions)
+func OutputsToSchema(modulePath string, opts ...Option) result.SchemaResult {
+	options := newOptions(opts)
+
+	module, loadErr := loadModule(modulePath)
+	if loadErr != nil {
+		return *loadErr
+	}
+
+	sch := new(schema.Schema)
+	sch.Type = "object"
+	sch.Properties = orderedmap.New[string, *schema.Schema]()
+
+	outputsResult := result.SchemaResult{
+		Schema: sch,
+		Diags:  []result.Diagnostic{},
+	}
+
+	inferrer := outputTypeInferrer{
+		module:    module,
+		variables: variablesToSchema(module, options).Schema,
+	}
+	blocks := loadOutputBlocks(module)
+
+	for _, output := range sortedOutputs(module) {
+		var outputSchema *schema.Schema
+		pointer := schema.AppendPointer("", "properties", output.Name)
+		block, blockExists := blocks[output.Name]
+		if blockExists {
+			if value, valueExists := block.block.Body.Attributes["value"]; valueExists {
+				inferrer.file = output.Pos.Filename
+				outputSchema = inferrer.infer(value.Expr, output.Name, pointer)
+			}
+		}
+
+		if outputSchema == nil {
+			outputSchema = inferrer.unknown(output.Name, pointer, output.Pos.Filename, outputValueRange(output, block))
+		}
+
+		outputSchema.Title = output.Name
+		if output.Description != "" {
+			outputSchema.Description = output.Description
+		}
+		if output.Sensitive {
+			hydrateSensitiveSchema(outputSchema)
+		}
+
+		sch.Properties.Set(output.Name, outputSchema)
+		sch.Required = append(sch.Required, output.Name)
+	}
+
+	slices.Sort(sch.Required)
+
+	if options.alphabetical {
+		orderPropertiesAlphabetically(sch)
+	}
+
+	outputsResult.Diags = append(outputsResult.Diags, inferrer.diags...)
+	return outputsResult
+}
+
+type outputTypeInferrer struct {
+	module    *tfconfig.Module
+	variables *schema.Schema
+	// the file of the output being inferred, and the values in it whose type couldn't be determined
+	file  string
+	diags []result.Diagnostic
+}
+
+// An empty schema for a value whose type couldn't be determined, with a warning pointing at it
+func (oi *outputTypeInferrer) unknown(path, pointer, file string, rng hcl.Range) *schema.Schema {
+	oi.diags = append(oi.diags, result.Diagnostic{
+		Path:          path,
+		Code:          "unknown_output_type",
+		Message:       fmt.Sprintf("unable to determine the type of output '%s' from its value", path),
+		Level:         result.Warning,
+		File:          file,
+		Range:         result.HCLRange(rng),
+		SchemaPointer: pointer,
+	})
+	return &schema.Schema{Comment: "Airlock warning: unable to determine the type of the output"}
+}
+
+// Infer the schema of an expression, nil if it can't be determined. The returned schema is always a
+// new node, so the caller can annotate it freely. Path and pointer locate the value in the outputs, for the
+// warnings about attributes of it whose type can't be determined
+func (oi *outputTypeInferrer) infer(expr hclsyntax.Expression, path, pointer string) *schema.Schema {
+	if val, ok := literalValue(expr); ok {
+		if val.IsNull() {
+			return nil
+		}
+		literalSchema := new(schema.Schema)
+		if diags := hydrateSchemaFromNameTypeAndDefaults(literalSchema, "", val.Type(), nil, "", nil); len(diags) > 0 {
+			return nil
+		}
+		return literalSchema
+	}
+
+	switch expr := unwrapExpr(expr).(type) {
+	case *hclsyntax.TemplateExpr:
+		// any string with interpolations is a string
+		return &schema.Schema{Type: "string"}
+	case *hclsyntax.ScopeTraversalExpr:
+		return oi.inferTraversal(expr.Traversal)
+	case *hclsyntax.FunctionCallExpr:
+		if returnType, known := functionReturnTypes[expr.Name]; known {
+			return &schema.Schema{Type: returnType}
+		}
+	case *hclsyntax.BinaryOpExpr:
+		switch expr.Op {
+		case hclsyntax.OpAdd, hclsyntax.OpSubtract, hclsyntax.OpMultiply, hclsyntax.OpDivide, hclsyntax.OpModulo:
+			return &schema.Schema{Type: "number"}
+		default:
+			return &schema.Schema{Type: "boolean"}
+		}
+	case *hclsyntax.UnaryOpExpr:
+		if expr.Op == hclsyntax.OpLogicalNot {
+			return &schema.Schema{Type: "boolean"}
+		}
+		return &schema.Schema{Type: "number"}
+	case *hclsyntax.ConditionalExpr:
+		// only the warnings of the branch that's used are kept
+		before := len(oi.diags)
+		trueSchema := oi.infer(expr.TrueResult, path, pointer)
+		afterTrue := len(oi.diags)
+		falseSchema := oi.infer(expr.FalseResult, path, pointer)
+		if trueSchema != nil && falseSchema != nil && len(trueSchema.TypeNames()) > 0 && slices.Equal(trueSchema.TypeNames(), falseSchema.TypeNames()) {
+			oi.diags = oi.diags[:afterTrue]
+			trueSchema.Default = nil
+			return trueSchema
+		}
+		oi.diags = oi.diags[:before]
+	case *hclsyntax.ObjectConsExpr:
+		return oi.inferObject(expr, path, pointer)
+	case *hclsyntax.TupleConsExpr:
+		return &schema.Schema{Type: "array"}
+	case *hclsyntax.ForExpr:
+		if expr.KeyExpr != nil {
+			return &schema.Schema{Type: "object"}
+		}
+		return &schema.Schema{Type: "array"}
+	}
+	return nil
+}
+
+func (oi *outputTypeInferrer) inferObject(expr *hclsyntax.ObjectConsExpr, path, pointer string) *schema.Schema {
+	sch := &schema.Schema{
+		Type:       "object",
+		Properties: orderedmap.New[string, *schema.Schema](),
+	}
+	before := len(oi.diags)
+	for _, item := range expr.Items {
+		name := hcl.ExprAsKeyword(item.KeyExpr)
+		if name == "" {
+			// computed keys, so the attributes aren't known
+			oi.diags = oi.diags[:before]
+			return &schema.Schema{Type: "object"}
+		}
+		propertyPath := path + "." + name
+		propertyPointer := schema.AppendPointer(pointer, "properties", name)
+		property := oi.infer(item.ValueExpr, propertyPath, propertyPointer)
+		if property == nil {
+			property = oi.unknown(propertyPath, propertyPointer, oi.file, item.ValueExpr.Range())
+		}
+		property.Title = name
+		property.Default = nil
+		sch.Properties.Set(name, property)
+		sch.Required = append(sch.Required, name)
+	}
+	slices.Sort(sch.Required)
+	return sch
+}
+
+func (oi *outputTypeInferrer) inferTraversal(traversal hcl.Traversal) *schema.Schema {
+	names := []string{}
+	for _, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, step.Name)
+		case hcl.TraverseAttr:
+			names = append(names, step.Name)
+		case hcl.TraverseIndex:
+			// indexes into count/for_each resources don't change the type of their attributes
+			if len(names) < 2 || names[0] == "var" {
+				return nil
+			}
+		default:
+			return nil
+		}
+	}
+
+	switch {
+	case len(names) >= 2 && names[0] == "var":
+		return oi.inferVariable(names[1:])
+	case len(names) == 4 && names[0] == "data":
+		if _, exists := oi.module.DataResources[strings.Join(names[:3], ".")]; exists && names[3] == "id" {
+			return &schema.Schema{Type: "string"}
+		}
+	case len(names) == 3:
+		// without provider schemas the only attribute we know the type of is the id, which is always a string
+		if _, exists := oi.module.ManagedResources[strings.Join(names[:2], ".")]; exists && names[2] == "id" {
+			return &schema.Schema{Type: "string"}
+		}
+	}
+	return nil
+}
+
+// A reference to a variable (or an attribute in one) has the same schema as the variable
+func (oi *outputTypeInferrer) inferVariable(names []string) *schema.Schema {
+	current := oi.variables
+	for _, name := range names {
+		if current == nil || current.Properties == nil {
+			return nil
+		}
+		current, _ = current.Properties.Get(name)
+	}
+	if current == nil {
+		return nil
+	}
+	copied, err := schema.Clone(current)
+	if err != nil {
+		return nil
+	}
+	// the output has the type and constraints of the variable, but not what only describes it as an input
+	_, _ = schema.Walk(copied, schema.Visitor{Pre: func(_ string, node *schema.Schema) (*schema.Schema, error) {
+		node.Default = nil
+		node.Description = ""
+		node.Examples = nil
+		node.Deprecated = false
+		return node, nil
+	}})
+	return copied
+}
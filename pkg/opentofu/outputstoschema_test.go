@@ -0,0 +1,73 @@
+package opentofu_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/opentofu"
+	"github.com/massdriver-cloud/airlock/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputsToSchema(t *testing.T) {
+	type testData struct {
+		name  string
+		diags []result.Diagnostic
+	}
+	tests := []testData{
+		{
+			name: "outputs",
+			diags: []result.Diagnostic{
+				{
+					Path:    "bucket_arn",
+					Code:    "unknown_output_type",
+					Message: "unable to determine the type of output 'bucket_arn' from its value",
+					Level:   result.Warning,
+					File:    "testdata/opentofu/outputs/main.tf",
+					Range: &result.Range{
+						Start: result.Position{Line: 24, Column: 11},
+						End:   result.Position{Line: 24, Column: 36},
+					},
+					SchemaPointer: "/properties/bucket_arn",
+				},
+				{
+					Path:    "connection.arn",
+					Code:    "unknown_output_type",
+					Message: "unable to determine the type of output 'connection.arn' from its value",
+					Level:   result.Warning,
+					File:    "testdata/opentofu/outputs/main.tf",
+					Range: &result.Range{
+						Start: result.Position{Line: 43, Column: 14},
+						End:   result.Position{Line: 43, Column: 39},
+					},
+					SchemaPointer: "/properties/connection/properties/arn",
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modulePath := filepath.Join("testdata/opentofu", tc.name)
+
+			got := opentofu.OutputsToSchema(modulePath)
+
+			gotSchema, marshalErr := json.Marshal(got.Schema)
+			if marshalErr != nil {
+				t.Fatalf("unexpected error: %s", marshalErr.Error())
+			}
+
+			wantSchema, readErr := os.ReadFile(filepath.Join("testdata/opentofu", tc.name, "schema.json"))
+			if readErr != nil {
+				t.Fatalf("unexpected error: %s", readErr.Error())
+			}
+
+			require.JSONEq(t, string(wantSchema), string(gotSchema))
+
+			assert.ElementsMatch(t, tc.diags, got.Diags)
+		})
+	}
+}
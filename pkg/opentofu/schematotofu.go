@@ -6,11 +6,25 @@ import (
 	"reflect"
 	"slices"
 
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/massdriver-cloud/airlock/pkg/result"
 	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/zclconf/go-cty/cty"
 )
 
+// SchemaToTofu converts a JSON schema to OpenTofu variable blocks
 func SchemaToTofu(in io.Reader) ([]byte, error) {
+	converted, err := SchemaToTofuWithDiagnostics(in)
+	if err != nil {
+		return nil, err
+	}
+	return converted.Code, nil
+}
+
+// SchemaToTofuWithDiagnostics converts a JSON schema to OpenTofu variable blocks, along with the recursive references left
+// unresolved and the conflicts found merging allOf/anyOf/oneOf and conditional branches
+func SchemaToTofuWithDiagnostics(in io.Reader) (*result.CodeResult, error) {
 	bytes, err := io.ReadAll(in)
 	if err != nil {
 		return nil, err
@@ -22,19 +36,28 @@ func SchemaToTofu(in io.Reader) ([]byte, error) {
 		return nil, err
 	}
 
+	recursiveRefs, err := schema.Resolve(&root, schema.SourcePath(in))
+	if err != nil {
+		return nil, err
+	}
+
 	f := hclwrite.NewEmptyFile()
 	rootBody := f.Body()
 
-	flattenedProperties := schema.ExpandProperties(&root)
+	flattened, conflicts := schema.Flatten(&root)
+	flattenedProperties := schema.ExpandProperties(flattened)
 	for prop := flattenedProperties.Oldest(); prop != nil; prop = prop.Next() {
-		required := slices.Contains(root.Required, prop.Key)
+		required := slices.Contains(flattened.Required, prop.Key)
 		err = createTopLevelVariableBlock(prop.Key, prop.Value, required, rootBody)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return f.Bytes(), nil
+	return &result.CodeResult{
+		Code:  f.Bytes(),
+		Diags: append(result.RecursiveRefDiagnostics(recursiveRefs), result.ConflictDiagnostics(conflicts)...),
+	}, nil
 }
 
 func createTopLevelVariableBlock(name string, param *schema.Schema, required bool, body *hclwrite.Body) error {
@@ -46,27 +69,62 @@ func createTopLevelVariableBlock(name string, param *schema.Schema, required boo
 		typeExprTokens(param, false),
 	)
 
+	setDescription(param, varBody)
+
 	// If this value isn't required, then we should set the default
 	if !required {
-		defaultValue, err := json.Marshal(param.Default)
+		defaultValue, err := interfaceToCtyValue(param.Default)
 		if err != nil {
 			return err
 		}
 
-		varBody.SetAttributeRaw(
-			"default",
-			hclwrite.TokensForIdentifier(string(defaultValue)),
-		)
+		varBody.SetAttributeValue("default", defaultValue)
+	}
+
+	setSensitive(param, varBody)
+	setNullable(param, required, varBody)
+
+	return appendValidationBlocks(name, param, required, varBody)
+}
+
+func setDescription(param *schema.Schema, body *hclwrite.Body) {
+	// the title is usually just a friendlier name, so only fall back to it if there's no description
+	description := param.Description
+	if description == "" {
+		description = param.Title
+	}
+	if description != "" {
+		body.SetAttributeValue("description", cty.StringVal(description))
+	}
+}
+
+func setSensitive(param *schema.Schema, body *hclwrite.Body) {
+	if param.WriteOnly || param.Format == "password" {
+		body.SetAttributeValue("sensitive", cty.True)
+	}
+}
+
+func setNullable(param *schema.Schema, required bool, body *hclwrite.Body) {
+	// a property that isn't required and has no default is null when it isn't set, and a type of [T, "null"] accepts null
+	_, nullable := param.NullableType()
+	if nullable || (!required && param.Default == nil) {
+		body.SetAttributeValue("nullable", cty.True)
 	}
-	return nil
 }
 
 func typeExprTokens(node *schema.Schema, optional bool) hclwrite.Tokens {
 	if optional {
+		if node.Default != nil {
+			if defaultValue, err := interfaceToCtyValue(node.Default); err == nil {
+				return hclwrite.TokensForFunctionCall("optional", typeExprTokens(node, false), hclwrite.TokensForValue(defaultValue))
+			}
+		}
 		return hclwrite.TokensForFunctionCall("optional", typeExprTokens(node, false))
 	}
 
-	switch node.Type {
+	// a type of [T, "null"] is just T, since OpenTofu types all accept null
+	nodeType, _ := node.NullableType()
+	switch nodeType {
 	case "string":
 		return hclwrite.TokensForIdentifier("string")
 	case "boolean":
@@ -83,6 +141,14 @@ func typeExprTokens(node *schema.Schema, optional bool) hclwrite.Tokens {
 }
 
 func convertArray(node *schema.Schema) hclwrite.Tokens {
+	// prefixItems describes a fixed shape list, which is a tuple
+	if len(node.PrefixItems) > 0 {
+		elements := make([]hclwrite.Tokens, len(node.PrefixItems))
+		for i, item := range node.PrefixItems {
+			elements[i] = typeExprTokens(item, false)
+		}
+		return hclwrite.TokensForFunctionCall("tuple", hclwrite.TokensForTuple(elements))
+	}
 	if node.Items != nil {
 		return hclwrite.TokensForFunctionCall("list", typeExprTokens(node.Items, false))
 	}
@@ -127,10 +193,15 @@ func convertMap(node *schema.Schema) hclwrite.Tokens {
 
 func convertObject(node *schema.Schema) hclwrite.Tokens {
 	// if any of the fields that imply there are dynamic properties exists, we need to try to interpret as a map
-	additionalPropertiesIsFalseOrNull := node.AdditionalProperties == nil || (reflect.TypeOf(node.AdditionalProperties).Kind() == reflect.Bool && !node.AdditionalProperties.(bool))
-	if !additionalPropertiesIsFalseOrNull || node.PatternProperties != nil {
+	if objectIsMap(node) {
 		return convertMap(node)
 	}
+	// object attributes have to be identifiers, so other property names can only be allowed by any
+	for prop := schema.ExpandProperties(node).Oldest(); prop != nil; prop = prop.Next() {
+		if !hclsyntax.ValidIdentifier(prop.Key) {
+			return hclwrite.TokensForIdentifier("any")
+		}
+	}
 
 	items := parseObject(node)
 
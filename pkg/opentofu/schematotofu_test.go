@@ -3,9 +3,12 @@ package opentofu_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/massdriver-cloud/airlock/pkg/opentofu"
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSchemaToTofu(t *testing.T) {
@@ -31,6 +34,30 @@ func TestSchemaToTofu(t *testing.T) {
 		{
 			name: "ifthenelse",
 		},
+		{
+			name: "validation",
+		},
+		{
+			name: "metadata",
+		},
+		{
+			name: "tuple",
+		},
+		{
+			name: "nesteddefaults",
+		},
+		{
+			name: "refs",
+		},
+		{
+			name: "nullable",
+		},
+		{
+			name: "allof",
+		},
+		{
+			name: "identifiers",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -55,3 +82,39 @@ func TestSchemaToTofu(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaToTofuWithDiagnostics(t *testing.T) {
+	type testData struct {
+		name      string
+		schema    string
+		wantCode  string
+		wantDiags []result.Diagnostic
+	}
+	tests := []testData{
+		{
+			name:     "conflict",
+			schema:   `{"properties": {"port": {"type": "integer"}}, "allOf": [{"properties": {"port": {"type": "string"}}}]}`,
+			wantCode: `variable "port"`,
+			wantDiags: []result.Diagnostic{
+				{Path: "port", Code: "merge_conflict", Message: "conflicting definitions of 'port': type integer conflicts with string", Level: result.Warning},
+			},
+		},
+		{
+			name:     "recursive ref",
+			schema:   `{"$defs": {"node": {"type": "object", "properties": {"child": {"$ref": "#/$defs/node"}}}}, "properties": {"tree": {"$ref": "#/$defs/node"}}, "required": ["tree"]}`,
+			wantCode: `child = optional(any)`,
+			wantDiags: []result.Diagnostic{
+				{Path: "/properties/tree/properties/child", Code: "recursive_ref", Message: `recursive $ref "#/$defs/node" can't be expanded, so any value is accepted`, Level: result.Warning, SchemaPointer: "/properties/tree/properties/child"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := opentofu.SchemaToTofuWithDiagnostics(strings.NewReader(tc.schema))
+			require.NoError(t, err)
+
+			require.Contains(t, string(got.Code), tc.wantCode)
+			require.Equal(t, tc.wantDiags, got.Diags)
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package opentofu
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// validationTarget is a value within a variable that constraints can be checked against
+type validationTarget struct {
+	// HCL reference to the value (var.foo, var.foo.bar, item.baz)
+	ref string
+	// human readable path to the value for error messages (foo, foo.bar, foo[*].baz)
+	label string
+	// the value itself may be null
+	nullable bool
+	// one of the values the reference passes through may be null, so accessing it directly could fail
+	nullableAncestor bool
+	// depth of the enclosing for expressions, used to name loop variables
+	depth int
+	// wraps the condition for enclosing for expressions, so item constraints are checked for every item
+	wrap func(string) string
+}
+
+type validation struct {
+	condition string
+	message   string
+}
+
+func appendValidationBlocks(name string, node *schema.Schema, required bool, body *hclwrite.Body) error {
+	target := validationTarget{
+		ref:      "var." + name,
+		label:    name,
+		nullable: !required,
+		wrap:     func(condition string) string { return condition },
+	}
+
+	validations, err := collectValidations(node, target)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range validations {
+		conditionTokens, parseErr := expressionTokens(v.condition)
+		if parseErr != nil {
+			return parseErr
+		}
+
+		validationBody := body.AppendNewBlock("validation", nil).Body()
+		validationBody.SetAttributeRaw("condition", conditionTokens)
+		validationBody.SetAttributeValue("error_message", cty.StringVal(v.message))
+	}
+	return nil
+}
+
+func collectValidations(node *schema.Schema, target validationTarget) ([]validation, error) {
+	nodeType, nullable := node.NullableType()
+	target.nullable = target.nullable || nullable
+
+	constraints, err := constraintValidations(node, target)
+	if err != nil {
+		return nil, err
+	}
+
+	validations := []validation{}
+	for _, v := range constraints {
+		validations = append(validations, validation{
+			condition: target.wrap(target.guard(v.condition)),
+			message:   v.message,
+		})
+	}
+
+	switch nodeType {
+	case "object":
+		if !objectIsMap(node) {
+			nested, nestedErr := collectPropertyValidations(node, target)
+			if nestedErr != nil {
+				return nil, nestedErr
+			}
+			validations = append(validations, nested...)
+		}
+	case "array":
+		if node.Items != nil {
+			nested, nestedErr := collectValidations(node.Items, target.items())
+			if nestedErr != nil {
+				return nil, nestedErr
+			}
+			validations = append(validations, nested...)
+		}
+	}
+
+	return validations, nil
+}
+
+func collectPropertyValidations(node *schema.Schema, target validationTarget) ([]validation, error) {
+	validations := []validation{}
+	flattenedProperties := schema.ExpandProperties(node)
+	for prop := flattenedProperties.Oldest(); prop != nil; prop = prop.Next() {
+		child := validationTarget{
+			ref:              attributeRef(target.ref, prop.Key),
+			label:            target.label + "." + prop.Key,
+			nullable:         !slices.Contains(node.Required, prop.Key),
+			nullableAncestor: target.nullable || target.nullableAncestor,
+			depth:            target.depth,
+			wrap:             target.wrap,
+		}
+		nested, err := collectValidations(prop.Value, child)
+		if err != nil {
+			return nil, err
+		}
+		validations = append(validations, nested...)
+	}
+	return validations, nil
+}
+
+// Attributes that aren't valid identifiers, like foo-bar, can only be accessed with an index
+func attributeRef(ref, name string) string {
+	if hclsyntax.ValidIdentifier(name) {
+		return ref + "." + name
+	}
+	return ref + "[" + string(hclwrite.TokensForValue(cty.StringVal(name)).Bytes()) + "]"
+}
+
+// The constraints of the items in a list are checked with alltrue([for item in list : <condition>])
+func (vt validationTarget) items() validationTarget {
+	loopVar := "item"
+	if vt.depth > 0 {
+		loopVar = fmt.Sprintf("item%d", vt.depth)
+	}
+	return validationTarget{
+		ref:   loopVar,
+		label: vt.label + "[*]",
+		depth: vt.depth + 1,
+		wrap: func(condition string) string {
+			return vt.wrap(vt.guard(fmt.Sprintf("alltrue([for %s in %s : %s])", loopVar, vt.ref, condition)))
+		},
+	}
+}
+
+// Conditions are evaluated even if the value is null, so nullable values are skipped with a conditional (which,
+// unlike || and &&, only evaluates the branch it needs)
+func (vt validationTarget) guard(condition string) string {
+	switch {
+	case vt.nullableAncestor:
+		return fmt.Sprintf("try(%s, null) == null ? true : %s", vt.ref, condition)
+	case vt.nullable:
+		return fmt.Sprintf("%s == null ? true : %s", vt.ref, condition)
+	default:
+		return condition
+	}
+}
+
+func constraintValidations(node *schema.Schema, target validationTarget) ([]validation, error) {
+	validations := []validation{}
+
+	if len(node.Enum) > 0 {
+		enum, err := hclLiteral(node.Enum)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, len(node.Enum))
+		for i, value := range node.Enum {
+			values[i] = fmt.Sprintf("%v", value)
+		}
+		validations = append(validations, validation{
+			condition: fmt.Sprintf("contains(%s, %s)", enum, target.ref),
+			message:   fmt.Sprintf("%s must be one of: %s", target.label, strings.Join(values, ", ")),
+		})
+	}
+
+	if node.Const != nil {
+		constant, err := hclLiteral(node.Const)
+		if err != nil {
+			return nil, err
+		}
+		validations = append(validations, validation{
+			condition: fmt.Sprintf("%s == %s", target.ref, constant),
+			message:   fmt.Sprintf("%s must be %v", target.label, node.Const),
+		})
+	}
+
+	nodeType, _ := node.NullableType()
+	switch nodeType {
+	case "string":
+		validations = append(validations, stringValidations(node, target)...)
+	case "integer", "number":
+		validations = append(validations, numberValidations(node, target)...)
+	case "array":
+		minItems, maxItems := node.MinItems, node.MaxItems
+		// tuples already enforce their own length
+		if len(node.PrefixItems) > 0 {
+			minItems = nilIfLength(minItems, len(node.PrefixItems))
+			maxItems = nilIfLength(maxItems, len(node.PrefixItems))
+		}
+		validations = append(validations, lengthValidations(target, minItems, maxItems, "contain at least %d item(s)", "contain at most %d item(s)")...)
+	}
+
+	return validations, nil
+}
+
+func stringValidations(node *schema.Schema, target validationTarget) []validation {
+	validations := []validation{}
+	if node.Pattern != "" {
+		pattern := string(hclwrite.TokensForValue(cty.StringVal(node.Pattern)).Bytes())
+		validations = append(validations, validation{
+			condition: fmt.Sprintf("can(regex(%s, %s))", pattern, target.ref),
+			message:   fmt.Sprintf("%s must match the pattern %s", target.label, node.Pattern),
+		})
+	}
+	return append(validations, lengthValidations(target, node.MinLength, node.MaxLength, "be at least %d characters long", "be at most %d characters long")...)
+}
+
+func numberValidations(node *schema.Schema, target validationTarget) []validation {
+	comparisons := []struct {
+		value    json.Number
+		operator string
+		message  string
+	}{
+		{node.Minimum, ">=", "greater than or equal to"},
+		{node.ExclusiveMinimum, ">", "greater than"},
+		{node.Maximum, "<=", "less than or equal to"},
+		{node.ExclusiveMaximum, "<", "less than"},
+	}
+
+	validations := []validation{}
+	for _, comparison := range comparisons {
+		if comparison.value == "" {
+			continue
+		}
+		validations = append(validations, validation{
+			condition: fmt.Sprintf("%s %s %s", target.ref, comparison.operator, comparison.value),
+			message:   fmt.Sprintf("%s must be %s %s", target.label, comparison.message, comparison.value),
+		})
+	}
+	return validations
+}
+
+func lengthValidations(target validationTarget, minimum, maximum *uint64, minMessage, maxMessage string) []validation {
+	validations := []validation{}
+	if minimum != nil {
+		validations = append(validations, validation{
+			condition: fmt.Sprintf("length(%s) >= %d", target.ref, *minimum),
+			message:   fmt.Sprintf("%s must "+minMessage, target.label, *minimum),
+		})
+	}
+	if maximum != nil {
+		validations = append(validations, validation{
+			condition: fmt.Sprintf("length(%s) <= %d", target.ref, *maximum),
+			message:   fmt.Sprintf("%s must "+maxMessage, target.label, *maximum),
+		})
+	}
+	return validations
+}
+
+func nilIfLength(value *uint64, length int) *uint64 {
+	if value != nil && *value == uint64(length) {
+		return nil
+	}
+	return value
+}
+
+// objects that allow dynamic properties are rendered as maps (or any), which have no attributes to validate
+func objectIsMap(node *schema.Schema) bool {
+	additionalPropertiesIsFalseOrNull := node.AdditionalProperties == nil || (reflect.TypeOf(node.AdditionalProperties).Kind() == reflect.Bool && !node.AdditionalProperties.(bool))
+	return !additionalPropertiesIsFalseOrNull || node.PatternProperties != nil
+}
+
+// Render an arbitrary JSON value as an HCL literal
+func hclLiteral(val any) (string, error) {
+	ctyVal, err := interfaceToCtyValue(val)
+	if err != nil {
+		return "", err
+	}
+	return string(hclwrite.TokensForValue(ctyVal).Bytes()), nil
+}
+
+func interfaceToCtyValue(val any) (cty.Value, error) {
+	valJSON, err := json.Marshal(val)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	ty, err := ctyjson.ImpliedType(valJSON)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return ctyjson.Unmarshal(valJSON, ty)
+}
+
+// Parse a generated expression into tokens so it's formatted like any other attribute
+func expressionTokens(expr string) (hclwrite.Tokens, error) {
+	f, diags := hclwrite.ParseConfig([]byte("expr = "+expr), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to generate expression %q: %s", expr, diags.Error())
+	}
+	return f.Body().GetAttribute("expr").Expr().BuildTokens(nil), nil
+}
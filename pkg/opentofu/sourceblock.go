@@ -0,0 +1,135 @@
+package opentofu
+
+import (
+	"os"
+	"strings"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/massdriver-cloud/terraform-config-inspect/tfconfig"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// tfconfig only extracts a subset of what can be set on a variable or output (name, type, description, default, sensitive).
+// Anything else (validation blocks, nullable, output values, etc) has to be read from the raw HCL block
+type sourceBlock struct {
+	block  *hclsyntax.Block
+	source []byte
+}
+
+// Index the variable blocks of the module by name
+func loadVariableBlocks(module *tfconfig.Module) map[string]*sourceBlock {
+	filenames := []string{}
+	for _, variable := range module.Variables {
+		filenames = append(filenames, variable.Pos.Filename)
+	}
+	return loadSourceBlocks(filenames, "variable")
+}
+
+// Index the output blocks of the module by name
+func loadOutputBlocks(module *tfconfig.Module) map[string]*sourceBlock {
+	filenames := []string{}
+	for _, output := range module.Outputs {
+		filenames = append(filenames, output.Pos.Filename)
+	}
+	return loadSourceBlocks(filenames, "output")
+}
+
+// Parse the native syntax files and index the blocks of the given type by their label.
+// JSON syntax files (*.tf.json) are skipped, so anything declared there has no block
+func loadSourceBlocks(filenames []string, blockType string) map[string]*sourceBlock {
+	blocks := map[string]*sourceBlock{}
+	parsed := map[string]bool{}
+
+	for _, filename := range filenames {
+		if parsed[filename] || !strings.HasSuffix(filename, ".tf") {
+			continue
+		}
+		parsed[filename] = true
+
+		src, readErr := os.ReadFile(filename)
+		if readErr != nil {
+			continue
+		}
+		file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+		if diags.HasErrors() {
+			continue
+		}
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type == blockType && len(block.Labels) == 1 {
+				blocks[block.Labels[0]] = &sourceBlock{block: block, source: src}
+			}
+		}
+	}
+
+	return blocks
+}
+
+// Returns the source text of an expression in the block
+func (sb *sourceBlock) exprSource(expr hclsyntax.Expression) string {
+	return string(expr.Range().SliceBytes(sb.source))
+}
+
+// Returns the value of a literal boolean attribute (nullable, ephemeral, etc), false if it's unset
+func (sb *sourceBlock) boolAttribute(name string) bool {
+	attr, exists := sb.block.Body.Attributes[name]
+	if !exists {
+		return false
+	}
+	val, ok := literalValue(attr.Expr)
+	return ok && val.RawEquals(cty.True)
+}
+
+// Returns true if a literal boolean attribute is explicitly set to false, as opposed to unset
+func (sb *sourceBlock) falseAttribute(name string) bool {
+	attr, exists := sb.block.Body.Attributes[name]
+	if !exists {
+		return false
+	}
+	val, ok := literalValue(attr.Expr)
+	return ok && val.RawEquals(cty.False)
+}
+
+// The range of the block declaring a variable. Variables without a block (declared in JSON syntax) only have the
+// line tfconfig recorded
+func variableRange(variable *tfconfig.Variable, block *sourceBlock) hcl.Range {
+	if block == nil {
+		return lineOnlyRange(variable.Pos)
+	}
+	return block.block.Range()
+}
+
+// The range of the type of a variable, falling back to the block when the type isn't set. Variables without a block
+// (declared in JSON syntax) only have the line tfconfig recorded
+func variableTypeRange(variable *tfconfig.Variable, block *sourceBlock) hcl.Range {
+	if block == nil {
+		return lineOnlyRange(variable.Pos)
+	}
+	if attr, exists := block.block.Body.Attributes["type"]; exists {
+		return attr.Expr.Range()
+	}
+	return block.block.DefRange()
+}
+
+// The range of the value of an output, falling back to the block when the value isn't set
+func outputValueRange(output *tfconfig.Output, block *sourceBlock) hcl.Range {
+	if block == nil {
+		return lineOnlyRange(output.Pos)
+	}
+	if attr, exists := block.block.Body.Attributes["value"]; exists {
+		return attr.Expr.Range()
+	}
+	return block.block.DefRange()
+}
+
+func lineOnlyRange(pos tfconfig.SourcePos) hcl.Range {
+	return hcl.Range{
+		Filename: pos.Filename,
+		Start:    hcl.Pos{Line: pos.Line},
+		End:      hcl.Pos{Line: pos.Line},
+	}
+}
@@ -0,0 +1,26 @@
+variable "password" {
+  type        = string
+  description = "The database admin password"
+  sensitive   = true
+}
+
+variable "session_token" {
+  type      = string
+  ephemeral = true
+}
+
+variable "instance_type" {
+  type     = string
+  nullable = true
+  default  = null
+  validation {
+    condition     = var.instance_type == null || can(regex("^t3\\.", var.instance_type))
+    error_message = "Only t3 instances are supported."
+  }
+}
+
+variable "replicas" {
+  type     = number
+  nullable = false
+  default  = 1
+}
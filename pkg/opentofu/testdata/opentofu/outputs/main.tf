@@ -0,0 +1,55 @@
+variable "bucket_name" {
+  type        = string
+  description = "The name of the bucket"
+}
+
+variable "settings" {
+  type = object({
+    versioning = optional(bool, false)
+    tags       = optional(map(string))
+  })
+  default = {}
+}
+
+resource "aws_s3_bucket" "example" {
+  bucket = var.bucket_name
+}
+
+output "bucket_id" {
+  description = "The ID of the bucket"
+  value       = aws_s3_bucket.example.id
+}
+
+output "bucket_arn" {
+  value = aws_s3_bucket.example.arn
+}
+
+output "bucket_name" {
+  value = var.bucket_name
+}
+
+output "versioning" {
+  value = var.settings.versioning
+}
+
+output "url" {
+  value = "https://${aws_s3_bucket.example.bucket_domain_name}"
+}
+
+output "connection" {
+  value = {
+    id     = aws_s3_bucket.example.id
+    region = "us-east-1"
+    arn    = aws_s3_bucket.example.arn
+  }
+  sensitive = true
+}
+
+output "token" {
+  value     = sha256(var.bucket_name)
+  sensitive = true
+}
+
+output "port" {
+  value = 443
+}
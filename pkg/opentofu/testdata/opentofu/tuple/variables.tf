@@ -0,0 +1,13 @@
+variable "coordinates" {
+  type = tuple([number, number])
+}
+
+variable "listener" {
+  type = tuple([string, number, bool])
+}
+
+variable "endpoint" {
+  type = object({
+    address = optional(tuple([string, number]), ["localhost", 443])
+  })
+}
@@ -0,0 +1,56 @@
+variable "name" {
+  type        = string
+  description = "The name of the bucket"
+  validation {
+    condition     = can(regex("^[a-z0-9-]+$", var.name)) && length(var.name) >= 3 && length(var.name) <= 63
+    error_message = "Bucket names must be 3-63 lowercase alphanumeric characters or hyphens."
+  }
+}
+
+variable "environment" {
+  type = string
+  validation {
+    condition     = contains(["dev", "staging", "prod"], var.environment)
+    error_message = "Environment must be one of dev, staging or prod."
+  }
+}
+
+variable "replicas" {
+  type = number
+  validation {
+    condition     = var.replicas >= 1 && 10 > var.replicas
+    error_message = "Replicas must be between 1 and 9."
+  }
+}
+
+variable "zones" {
+  type = list(string)
+  validation {
+    condition     = length(var.zones) > 0
+    error_message = "At least one zone is required."
+  }
+  validation {
+    condition     = alltrue([for zone in var.zones : can(regex("^us-", zone))])
+    error_message = "Zones must be in the US."
+  }
+}
+
+variable "settings" {
+  type = object({
+    tier = optional(string)
+  })
+  default = null
+  validation {
+    condition     = var.settings == null || contains(["basic", "premium"], var.settings.tier)
+    error_message = "Tier must be basic or premium."
+  }
+}
+
+variable "cidr" {
+  type        = string
+  description = "The VPC CIDR block"
+  validation {
+    condition     = can(cidrhost(var.cidr, 0))
+    error_message = "Must be a valid CIDR block."
+  }
+}
@@ -0,0 +1,32 @@
+variable "name" {
+  type = string
+  validation {
+    condition     = length(var.name) >= 2
+    error_message = "name must be at least 2 characters long"
+  }
+  validation {
+    condition     = length(var.name) <= 20
+    error_message = "name must be at most 20 characters long"
+  }
+}
+variable "size" {
+  type = number
+  validation {
+    condition     = contains([1, 2, 3], var.size)
+    error_message = "size must be one of: 1, 2, 3"
+  }
+}
+variable "engine" {
+  type     = any
+  default  = null
+  nullable = true
+  validation {
+    condition     = var.engine == null ? true : contains(["postgres", "mysql"], var.engine)
+    error_message = "engine must be one of: postgres, mysql"
+  }
+}
+variable "replicas" {
+  type     = number
+  default  = null
+  nullable = true
+}
@@ -1,6 +1,7 @@
 variable "emptytest" {
-  type    = string
-  default = null
+  type     = string
+  default  = null
+  nullable = true
 }
 variable "stringtest" {
   type    = string
@@ -26,7 +27,13 @@ variable "objecttest" {
   type = object({
     foo = string
   })
-  default = {"foo":"bar"}
+  default = {
+    foo = "bar"
+  }
+}
+variable "templatetest" {
+  type    = string
+  default = "$${not_interpolated} \"quoted\""
 }
 variable "requiredtest" {
   type = string
@@ -4,4 +4,8 @@ variable "single" {
     bar = optional(number)
     baz = optional(string)
   })
+  validation {
+    condition     = contains([false, true], var.single.foo)
+    error_message = "single.foo must be one of: false, true"
+  }
 }
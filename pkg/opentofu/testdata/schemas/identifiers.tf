@@ -0,0 +1,11 @@
+variable "labels" {
+  type = any
+  validation {
+    condition     = length(var.labels["app.kubernetes.io/name"]) <= 63
+    error_message = "labels.app.kubernetes.io/name must be at most 63 characters long"
+  }
+  validation {
+    condition     = can(regex("^[a-z]+$", var.labels["$${team}"]))
+    error_message = "labels.$${team} must match the pattern ^[a-z]+$"
+  }
+}
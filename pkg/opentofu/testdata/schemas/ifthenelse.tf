@@ -7,9 +7,11 @@ variable "then" {
     another = optional(string)
     nested  = optional(string)
   })
-  default = null
+  default  = null
+  nullable = true
 }
 variable "else" {
-  type    = string
-  default = null
+  type     = string
+  default  = null
+  nullable = true
 }
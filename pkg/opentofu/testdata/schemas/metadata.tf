@@ -0,0 +1,21 @@
+variable "password" {
+  type        = string
+  description = "The database admin password"
+  sensitive   = true
+}
+variable "token" {
+  type        = string
+  description = "API token"
+  sensitive   = true
+}
+variable "region" {
+  type        = string
+  description = "The region to deploy into"
+  default     = "us-east-1"
+}
+variable "suffix" {
+  type        = string
+  description = "Name suffix"
+  default     = null
+  nullable    = true
+}
@@ -0,0 +1,16 @@
+variable "database" {
+  type = object({
+    name       = string
+    engine     = optional(string, "postgres")
+    port       = optional(number, 5432)
+    multi_az   = optional(bool, false)
+    parameters = optional(list(string), ["log_connections", "log_disconnections"])
+    backup = optional(object({
+      retention_days = optional(number, 7)
+      window         = optional(string)
+      }), {
+      retention_days = 14
+      window         = "03:00-04:00"
+    })
+  })
+}
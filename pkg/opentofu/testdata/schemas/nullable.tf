@@ -0,0 +1,17 @@
+variable "name" {
+  type     = string
+  nullable = true
+  validation {
+    condition     = var.name == null ? true : can(regex("^[a-z]+$", var.name))
+    error_message = "name must match the pattern ^[a-z]+$"
+  }
+}
+variable "settings" {
+  type = object({
+    replicas = number
+  })
+  validation {
+    condition     = var.settings.replicas == null ? true : var.settings.replicas >= 1
+    error_message = "settings.replicas must be greater than or equal to 1"
+  }
+}
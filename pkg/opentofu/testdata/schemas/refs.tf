@@ -0,0 +1,22 @@
+variable "primary" {
+  type = object({
+    host = string
+    port = optional(number)
+  })
+  description = "The primary endpoint"
+  validation {
+    condition     = var.primary.port == null ? true : var.primary.port >= 1
+    error_message = "primary.port must be greater than or equal to 1"
+  }
+}
+variable "replicas" {
+  type = list(object({
+    host = string
+    port = optional(number)
+  }))
+  default = []
+  validation {
+    condition     = var.replicas == null ? true : alltrue([for item in var.replicas : item.port == null ? true : item.port >= 1])
+    error_message = "replicas[*].port must be greater than or equal to 1"
+  }
+}
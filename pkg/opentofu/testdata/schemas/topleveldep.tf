@@ -1,7 +1,12 @@
 variable "single" {
   type = string
+  validation {
+    condition     = contains(["something", "somethingelse"], var.single)
+    error_message = "single must be one of: something, somethingelse"
+  }
 }
 variable "foo" {
-  type    = string
-  default = null
+  type     = string
+  default  = null
+  nullable = true
 }
@@ -0,0 +1,6 @@
+variable "coordinates" {
+  type = tuple([number, number])
+}
+variable "listeners" {
+  type = list(tuple([string, number, bool]))
+}
@@ -0,0 +1,70 @@
+variable "name" {
+  type = string
+  validation {
+    condition     = can(regex("^[a-z0-9-]+$", var.name))
+    error_message = "name must match the pattern ^[a-z0-9-]+$"
+  }
+  validation {
+    condition     = length(var.name) >= 3
+    error_message = "name must be at least 3 characters long"
+  }
+  validation {
+    condition     = length(var.name) <= 63
+    error_message = "name must be at most 63 characters long"
+  }
+}
+variable "environment" {
+  type = string
+  validation {
+    condition     = contains(["dev", "staging", "prod"], var.environment)
+    error_message = "environment must be one of: dev, staging, prod"
+  }
+}
+variable "replicas" {
+  type = number
+  validation {
+    condition     = var.replicas >= 1
+    error_message = "replicas must be greater than or equal to 1"
+  }
+  validation {
+    condition     = var.replicas < 10
+    error_message = "replicas must be less than 10"
+  }
+}
+variable "zones" {
+  type = list(string)
+  validation {
+    condition     = length(var.zones) >= 1
+    error_message = "zones must contain at least 1 item(s)"
+  }
+  validation {
+    condition     = length(var.zones) <= 3
+    error_message = "zones must contain at most 3 item(s)"
+  }
+  validation {
+    condition     = alltrue([for item in var.zones : can(regex("^us-", item))])
+    error_message = "zones[*] must match the pattern ^us-"
+  }
+}
+variable "form" {
+  type = object({
+    kind = string
+    age  = optional(number)
+  })
+  validation {
+    condition     = var.form.kind == "person"
+    error_message = "form.kind must be person"
+  }
+  validation {
+    condition     = var.form.age == null ? true : var.form.age <= 150
+    error_message = "form.age must be less than or equal to 150"
+  }
+}
+variable "tier" {
+  type    = string
+  default = "basic"
+  validation {
+    condition     = var.tier == null ? true : contains(["basic", "premium"], var.tier)
+    error_message = "tier must be one of: basic, premium"
+  }
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/typeexpr"
@@ -17,10 +19,27 @@ import (
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
-func TofuToSchema(modulePath string) result.SchemaResult {
+func TofuToSchema(modulePath string, opts ...Option) result.SchemaResult {
+	options := newOptions(opts)
+
+	module, loadErr := loadModule(modulePath)
+	if loadErr != nil {
+		return *loadErr
+	}
+
+	result := variablesToSchema(module, options)
+
+	if options.alphabetical {
+		orderPropertiesAlphabetically(result.Schema)
+	}
+
+	return result
+}
+
+func loadModule(modulePath string) (*tfconfig.Module, *result.SchemaResult) {
 	module, err := tfconfig.LoadModule(modulePath)
 	if err != nil {
-		return result.SchemaResult{
+		return nil, &result.SchemaResult{
 			Schema: nil,
 			Diags: []result.Diagnostic{
 				{
@@ -28,37 +47,54 @@ func TofuToSchema(modulePath string) result.SchemaResult {
 					Code:    "module_load_error",
 					Message: fmt.Sprintf("failed to load module: %s", err),
 					Level:   result.Error,
+					File:    modulePath,
 				},
 			},
 		}
 	}
+	return module, nil
+}
 
+func variablesToSchema(module *tfconfig.Module, options options) result.SchemaResult {
 	sch := new(schema.Schema)
 	sch.Properties = orderedmap.New[string, *schema.Schema]()
 
-	result := result.SchemaResult{
-		Schema: sch,
-		Diags:  []result.Diagnostic{},
+	schemaResult := result.SchemaResult{
+		Schema:    sch,
+		Diags:     []result.Diagnostic{},
+		Locations: map[string]result.Location{},
 	}
 
-	for _, variable := range module.Variables {
-		variableSchema, diags := variableToSchema(variable, result.Diags)
-		result.Diags = diags
+	blocks := loadVariableBlocks(module)
+
+	for _, variable := range sortedVariables(module) {
+		block := blocks[variable.Name]
+		variableSchema, diags := variableToSchema(variable, block, options, schemaResult.Diags)
+		schemaResult.Diags = diags
 
 		if variableSchema == nil {
 			continue
 		}
 
+		blockRange := variableRange(variable, block)
+		schemaResult.Locations[schema.AppendPointer("", "properties", variable.Name)] = result.Location{
+			File:  blockRange.Filename,
+			Range: result.HCLRange(blockRange),
+		}
+
 		sch.Properties.Set(variable.Name, variableSchema)
 		sch.Required = append(sch.Required, variable.Name)
 	}
 
 	slices.Sort(sch.Required)
 
-	return result
+	return schemaResult
 }
 
-func variableToSchema(variable *tfconfig.Variable, diags []result.Diagnostic) (*schema.Schema, []result.Diagnostic) {
+func variableToSchema(variable *tfconfig.Variable, block *sourceBlock, options options, diags []result.Diagnostic) (*schema.Schema, []result.Diagnostic) {
+	pointer := schema.AppendPointer("", "properties", variable.Name)
+	typeRange := variableTypeRange(variable, block)
+
 	schema := new(schema.Schema)
 	variableType, defaults, typeErr := variableTypeStringToCtyType(variable.Type)
 	if typeErr != nil {
@@ -67,6 +103,8 @@ func variableToSchema(variable *tfconfig.Variable, diags []result.Diagnostic) (*
 			Code:    "variable_type_error",
 			Message: fmt.Sprintf("failed to parse type %q: %s", variable.Type, typeErr),
 			Level:   result.Error,
+			File:    typeRange.Filename,
+			Range:   result.HCLRange(typeRange),
 		})
 		return nil, diags
 	}
@@ -79,7 +117,17 @@ func variableToSchema(variable *tfconfig.Variable, diags []result.Diagnostic) (*
 			variable.Name: defaults,
 		}
 	}
-	diags = hydrateSchemaFromNameTypeAndDefaults(schema, variable.Name, variableType, topLevelDefault, diags)
+	hydrateStart := len(diags)
+	diags = hydrateSchemaFromNameTypeAndDefaults(schema, variable.Name, variableType, topLevelDefault, pointer, diags)
+	// the problems with the type are all in the type expression
+	for index := hydrateStart; index < len(diags); index++ {
+		diags[index].File = typeRange.Filename
+		diags[index].Range = result.HCLRange(typeRange)
+	}
+
+	if typeExpr, parseErr := parseVariableType(variable.Type); parseErr == nil {
+		orderPropertiesBySource(schema, typeExpr)
+	}
 
 	schema.Description = variable.Description
 
@@ -91,16 +139,39 @@ func variableToSchema(variable *tfconfig.Variable, diags []result.Diagnostic) (*
 		schema.Default = false
 	}
 
+	if variable.Sensitive {
+		hydrateSensitiveSchema(schema)
+	}
+
+	if block != nil {
+		diags = applyValidations(schema, variable.Name, block, pointer, diags)
+
+		if block.boolAttribute("ephemeral") {
+			appendComment(schema, "ephemeral: this value is only available during the run and is never persisted to state or plan files")
+		}
+	}
+	if block != nil && block.boolAttribute("nullable") || options.implicitNullable && (block == nil || !block.falseAttribute("nullable")) {
+		hydrateNullableSchema(schema)
+	}
+
 	return schema, diags
 }
 
-func variableTypeStringToCtyType(variableType string) (cty.Type, *typeexpr.Defaults, error) {
+func parseVariableType(variableType string) (hclsyntax.Expression, error) {
 	if variableType == "" {
 		variableType = "any"
 	}
 	expr, diags := hclsyntax.ParseExpression([]byte(variableType), "", hcl.Pos{Line: 1, Column: 1})
 	if len(diags) != 0 {
-		return cty.NilType, nil, errors.New(diags.Error())
+		return nil, errors.New(diags.Error())
+	}
+	return expr, nil
+}
+
+func variableTypeStringToCtyType(variableType string) (cty.Type, *typeexpr.Defaults, error) {
+	expr, err := parseVariableType(variableType)
+	if err != nil {
+		return cty.NilType, nil, err
 	}
 	ty, defaults, diags := typeexpr.TypeConstraintWithDefaults(expr)
 	if len(diags) != 0 {
@@ -109,7 +180,7 @@ func variableTypeStringToCtyType(variableType string) (cty.Type, *typeexpr.Defau
 	return ty, defaults, nil
 }
 
-func hydrateSchemaFromNameTypeAndDefaults(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, diags []result.Diagnostic) []result.Diagnostic {
+func hydrateSchemaFromNameTypeAndDefaults(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Title = name
 
 	if defaults != nil {
@@ -122,22 +193,25 @@ func hydrateSchemaFromNameTypeAndDefaults(sch *schema.Schema, name string, ty ct
 	if ty.IsPrimitiveType() {
 		hydratePrimitiveSchema(sch, ty)
 	} else if ty.IsMapType() {
-		return hydrateMapSchema(sch, name, ty, defaults, diags)
+		return hydrateMapSchema(sch, name, ty, defaults, pointer, diags)
 	} else if ty.IsObjectType() {
-		return hydrateObjectSchema(sch, name, ty, defaults, diags)
+		return hydrateObjectSchema(sch, name, ty, defaults, pointer, diags)
 	} else if ty.IsListType() {
-		return hydrateArraySchema(sch, name, ty, defaults, diags)
+		return hydrateArraySchema(sch, name, ty, defaults, pointer, diags)
 	} else if ty.IsSetType() {
-		return hydrateSetSchema(sch, name, ty, defaults, diags)
+		return hydrateSetSchema(sch, name, ty, defaults, pointer, diags)
+	} else if ty.IsTupleType() {
+		return hydrateTupleSchema(sch, name, ty, defaults, pointer, diags)
 	} else if ty.HasDynamicTypes() {
-		return hydrateAnySchema(sch, diags)
+		return hydrateAnySchema(sch, pointer, diags)
 	} else {
 		sch.Comment = fmt.Sprintf("unsupported OpenTofu/Terraform type '%s'", ty.FriendlyName())
 		return append(diags, result.Diagnostic{
-			Path:    name,
-			Code:    "unsupported_type",
-			Message: fmt.Sprintf("unsupported OpenTofu/Terraform type '%s' in field '%s'", ty.FriendlyName(), name),
-			Level:   result.Warning,
+			Path:          name,
+			Code:          "unsupported_type",
+			Message:       fmt.Sprintf("unsupported OpenTofu/Terraform type '%s' in field '%s'", ty.FriendlyName(), name),
+			Level:         result.Warning,
+			SchemaPointer: pointer,
 		})
 	}
 	return diags
@@ -154,12 +228,12 @@ func hydratePrimitiveSchema(sch *schema.Schema, ty cty.Type) {
 	}
 }
 
-func hydrateObjectSchema(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, diags []result.Diagnostic) []result.Diagnostic {
+func hydrateObjectSchema(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "object"
 	sch.Properties = orderedmap.New[string, *schema.Schema]()
 	for attName, attType := range ty.AttributeTypes() {
 		attributeSchema := new(schema.Schema)
-		diags = hydrateSchemaFromNameTypeAndDefaults(attributeSchema, attName, attType, getDefaultChildren(name, defaults), diags)
+		diags = hydrateSchemaFromNameTypeAndDefaults(attributeSchema, attName, attType, getDefaultChildren(name, defaults), schema.AppendPointer(pointer, "properties", attName), diags)
 		sch.Properties.Set(attName, attributeSchema)
 		if !ty.AttributeOptional(attName) {
 			sch.Required = append(sch.Required, attName)
@@ -169,36 +243,91 @@ func hydrateObjectSchema(sch *schema.Schema, name string, ty cty.Type, defaults
 	return diags
 }
 
-func hydrateMapSchema(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, diags []result.Diagnostic) []result.Diagnostic {
+func hydrateMapSchema(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "object"
 	sch.PropertyNames = &schema.Schema{
 		Pattern: "^.*$",
 	}
 	sch.AdditionalProperties = new(schema.Schema)
-	return hydrateSchemaFromNameTypeAndDefaults(sch.AdditionalProperties.(*schema.Schema), "", ty.ElementType(), getDefaultChildren(name, defaults), diags)
+	return hydrateSchemaFromNameTypeAndDefaults(sch.AdditionalProperties.(*schema.Schema), "", ty.ElementType(), getDefaultChildren(name, defaults), schema.AppendPointer(pointer, "additionalProperties"), diags)
 }
 
-func hydrateArraySchema(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, diags []result.Diagnostic) []result.Diagnostic {
+func hydrateArraySchema(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Type = "array"
 	sch.Items = new(schema.Schema)
-	return hydrateSchemaFromNameTypeAndDefaults(sch.Items, "", ty.ElementType(), getDefaultChildren(name, defaults), diags)
+	return hydrateSchemaFromNameTypeAndDefaults(sch.Items, "", ty.ElementType(), getDefaultChildren(name, defaults), schema.AppendPointer(pointer, "items"), diags)
 }
 
-func hydrateSetSchema(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, diags []result.Diagnostic) []result.Diagnostic {
+func hydrateSetSchema(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.UniqueItems = true
-	return hydrateArraySchema(sch, name, ty, defaults, diags)
+	return hydrateArraySchema(sch, name, ty, defaults, pointer, diags)
 }
 
-func hydrateAnySchema(sch *schema.Schema, diags []result.Diagnostic) []result.Diagnostic {
+func hydrateTupleSchema(sch *schema.Schema, name string, ty cty.Type, defaults *typeexpr.Defaults, pointer string, diags []result.Diagnostic) []result.Diagnostic {
+	sch.Type = "array"
+	elementTypes := ty.TupleElementTypes()
+	sch.PrefixItems = make([]*schema.Schema, len(elementTypes))
+	for index, elementType := range elementTypes {
+		elementSchema := new(schema.Schema)
+		// tuple element defaults are keyed by their index
+		diags = hydrateSchemaFromNameTypeAndDefaults(elementSchema, strconv.Itoa(index), elementType, getDefaultChildren(name, defaults), schema.AppendPointer(pointer, "prefixItems", strconv.Itoa(index)), diags)
+		elementSchema.Title = ""
+		sch.PrefixItems[index] = elementSchema
+	}
+	// a tuple has exactly as many items as element types
+	length := uint64(len(elementTypes))
+	sch.MinItems = &length
+	sch.MaxItems = &length
+	sch.Items = schema.Boolean(false)
+	return diags
+}
+
+func hydrateAnySchema(sch *schema.Schema, pointer string, diags []result.Diagnostic) []result.Diagnostic {
 	sch.Comment = "Airlock warning: unconstrained type from OpenTofu/Terraform 'any'"
 	return append(diags, result.Diagnostic{
-		Path:    sch.Title,
-		Code:    "unconstrained_type",
-		Message: fmt.Sprintf("unconstrained type in field '%s' from OpenTofu/Terraform 'any'", sch.Title),
-		Level:   result.Warning,
+		Path:          sch.Title,
+		Code:          "unconstrained_type",
+		Message:       fmt.Sprintf("unconstrained type in field '%s' from OpenTofu/Terraform 'any'", sch.Title),
+		Level:         result.Warning,
+		SchemaPointer: pointer,
 	})
 }
 
+// Sensitive values are masked the same way bicep secure params are
+func hydrateSensitiveSchema(sch *schema.Schema) {
+	sch.WriteOnly = true
+	if schemaType, _ := sch.NullableType(); schemaType == "string" {
+		sch.Format = "password"
+	}
+}
+
+// A variable with 'nullable = true' accepts null in addition to its type, so its type becomes [T, "null"].
+// An enum (or const) has to list null explicitly as well
+func hydrateNullableSchema(sch *schema.Schema) {
+	schemaType, nullable := sch.NullableType()
+	if schemaType == "" || nullable {
+		// unconstrained types already accept null
+		return
+	}
+	sch.SetTypeNames([]string{schemaType, "null"})
+
+	if sch.Const != nil {
+		sch.Enum = []any{sch.Const}
+		sch.Const = nil
+	}
+	if len(sch.Enum) > 0 && !slices.Contains(sch.Enum, nil) {
+		sch.Enum = append(sch.Enum, nil)
+	}
+}
+
+func appendComment(sch *schema.Schema, comment string) {
+	if sch.Comment == "" {
+		sch.Comment = comment
+		return
+	}
+	sch.Comment = strings.Join([]string{sch.Comment, comment}, "\n")
+}
+
 func ctyValueToInterface(val cty.Value) interface{} {
 	valJSON, err := ctyjson.Marshal(val, val.Type())
 	if err != nil {
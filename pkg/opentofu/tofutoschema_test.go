@@ -27,21 +27,64 @@ func TestTofuToSchema(t *testing.T) {
 					Code:    "unconstrained_type",
 					Message: "unconstrained type in field 'any' from OpenTofu/Terraform 'any'",
 					Level:   result.Warning,
+					File:    "testdata/opentofu/simple/variables.tf",
+					Range: &result.Range{
+						Start: result.Position{Line: 94, Column: 10},
+						End:   result.Position{Line: 94, Column: 13},
+					},
+					SchemaPointer: "/properties/any",
 				},
 				{
 					Path:    "foo",
 					Code:    "unconstrained_type",
 					Message: "unconstrained type in field 'foo' from OpenTofu/Terraform 'any'",
 					Level:   result.Warning,
+					File:    "testdata/opentofu/simple/variables.tf",
+					Range: &result.Range{
+						Start: result.Position{Line: 98, Column: 10},
+						End:   result.Position{Line: 100, Column: 5},
+					},
+					SchemaPointer: "/properties/nestedany/properties/foo",
 				},
 				{
 					Path:    "empty",
 					Code:    "unconstrained_type",
 					Message: "unconstrained type in field 'empty' from OpenTofu/Terraform 'any'",
 					Level:   result.Warning,
+					File:    "testdata/opentofu/simple/variables.tf",
+					Range: &result.Range{
+						Start: result.Position{Line: 103, Column: 1},
+						End:   result.Position{Line: 103, Column: 17},
+					},
+					SchemaPointer: "/properties/empty",
 				},
 			},
 		},
+		{
+			name: "validation",
+			diags: []result.Diagnostic{
+				{
+					Path:    "cidr",
+					Code:    "untranslated_validation",
+					Message: "unable to translate validation condition in variable 'cidr' to JSON Schema: can(cidrhost(var.cidr, 0))",
+					Level:   result.Warning,
+					File:    "testdata/opentofu/validation/variables.tf",
+					Range: &result.Range{
+						Start: result.Position{Line: 53, Column: 21},
+						End:   result.Position{Line: 53, Column: 47},
+					},
+					SchemaPointer: "/properties/cidr",
+				},
+			},
+		},
+		{
+			name:  "metadata",
+			diags: []result.Diagnostic{},
+		},
+		{
+			name:  "tuple",
+			diags: []result.Diagnostic{},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -67,3 +110,98 @@ func TestTofuToSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestTofuToSchemaOrder(t *testing.T) {
+	type testData struct {
+		name     string
+		opts     []opentofu.Option
+		want     []string
+		wantAttr []string
+	}
+	tests := []testData{
+		{
+			name:     "source",
+			want:     []string{"teststring", "testnumber", "testbool", "testemptybool", "testobject", "testnestedobject", "testlist", "testset", "testmap", "nodescription", "any", "nestedany", "empty"},
+			wantAttr: []string{"name", "address", "age", "dead", "phones", "children"},
+		},
+		{
+			name:     "alphabetical",
+			opts:     []opentofu.Option{opentofu.WithAlphabeticalOrder()},
+			want:     []string{"any", "empty", "nestedany", "nodescription", "testbool", "testemptybool", "testlist", "testmap", "testnestedobject", "testnumber", "testobject", "testset", "teststring"},
+			wantAttr: []string{"address", "age", "children", "dead", "name", "phones"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := opentofu.TofuToSchema("testdata/opentofu/simple", tc.opts...)
+
+			gotNames := []string{}
+			for prop := got.Schema.Properties.Oldest(); prop != nil; prop = prop.Next() {
+				gotNames = append(gotNames, prop.Key)
+			}
+			assert.Equal(t, tc.want, gotNames)
+
+			nested, _ := got.Schema.Properties.Get("testnestedobject")
+			gotAttrs := []string{}
+			for prop := nested.Properties.Oldest(); prop != nil; prop = prop.Next() {
+				gotAttrs = append(gotAttrs, prop.Key)
+			}
+			assert.Equal(t, tc.wantAttr, gotAttrs)
+		})
+	}
+}
+
+func TestTofuToSchemaImplicitNullable(t *testing.T) {
+	module := t.TempDir()
+	source := `variable "implicit" {
+  type = string
+}
+
+variable "nullable" {
+  type     = string
+  nullable = true
+}
+
+variable "not_nullable" {
+  type     = string
+  nullable = false
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(module, "variables.tf"), []byte(source), 0o600))
+
+	type testData struct {
+		name string
+		opts []opentofu.Option
+		want map[string][]string
+	}
+	tests := []testData{
+		{
+			name: "explicit",
+			want: map[string][]string{"implicit": {"string"}, "nullable": {"string", "null"}, "not_nullable": {"string"}},
+		},
+		{
+			name: "implicit",
+			opts: []opentofu.Option{opentofu.WithImplicitNullable()},
+			want: map[string][]string{"implicit": {"string", "null"}, "nullable": {"string", "null"}, "not_nullable": {"string"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := opentofu.TofuToSchema(module, tc.opts...)
+
+			gotTypes := map[string][]string{}
+			for prop := got.Schema.Properties.Oldest(); prop != nil; prop = prop.Next() {
+				gotTypes[prop.Key] = prop.Value.TypeNames()
+			}
+			assert.Equal(t, tc.want, gotTypes)
+
+			assert.Equal(t, result.Location{
+				File: filepath.Join(module, "variables.tf"),
+				Range: &result.Range{
+					Start: result.Position{Line: 5, Column: 1},
+					End:   result.Position{Line: 8, Column: 2},
+				},
+			}, got.Locations["/properties/nullable"])
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package opentofu
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// validationTranslator maps the condition of a variable validation block onto JSON Schema keywords.
+// Only the common shapes of conditions are recognized:
+//
+//	can(regex("^[a-z]+$", var.x))          -> pattern
+//	contains(["a", "b"], var.x)            -> enum
+//	length(var.x) >= 1                     -> minLength/minItems/minProperties
+//	var.x <= 10                            -> maximum
+//	var.x == "foo"                         -> const
+//	alltrue([for v in var.x : <condition>]) -> <condition> applied to the items
+//
+// along with any combination of those joined by '&&', and null checks ('var.x == null || <condition>'
+// or 'var.x == null ? true : <condition>')
+type validationTranslator struct {
+	name  string
+	root  *schema.Schema
+	scope map[string]*schema.Schema
+}
+
+func applyValidations(sch *schema.Schema, name string, sb *sourceBlock, pointer string, diags []result.Diagnostic) []result.Diagnostic {
+	for _, block := range sb.block.Body.Blocks {
+		if block.Type != "validation" {
+			continue
+		}
+
+		condition, exists := block.Body.Attributes["condition"]
+		if !exists {
+			continue
+		}
+
+		translator := validationTranslator{
+			name:  name,
+			root:  sch,
+			scope: map[string]*schema.Schema{},
+		}
+		if !translator.translate(condition.Expr) {
+			diags = append(diags, result.Diagnostic{
+				Path:          name,
+				Code:          "untranslated_validation",
+				Message:       fmt.Sprintf("unable to translate validation condition in variable '%s' to JSON Schema: %s", name, sb.exprSource(condition.Expr)),
+				Level:         result.Warning,
+				File:          condition.Expr.Range().Filename,
+				Range:         result.HCLRange(condition.Expr.Range()),
+				SchemaPointer: pointer,
+			})
+		}
+
+		if errorMessage, messageExists := block.Body.Attributes["error_message"]; messageExists {
+			addValidationMessage(sch, validationMessage(errorMessage.Expr, sb))
+		}
+	}
+	return diags
+}
+
+// The error message is kept as the description if there isn't one already, otherwise it goes into the $comment
+func addValidationMessage(sch *schema.Schema, message string) {
+	switch {
+	case message == "":
+		return
+	case sch.Description == "":
+		sch.Description = message
+	default:
+		appendComment(sch, message)
+	}
+}
+
+func validationMessage(expr hclsyntax.Expression, sb *sourceBlock) string {
+	if val, ok := literalValue(expr); ok && val.Type() == cty.String && !val.IsNull() {
+		return val.AsString()
+	}
+	// error messages can be templates which reference the variable, so fall back to the raw text
+	return strings.Trim(sb.exprSource(expr), `"`)
+}
+
+// Translate a condition expression into schema constraints. Returns false if any part of the condition couldn't be translated
+func (vt *validationTranslator) translate(expr hclsyntax.Expression) bool {
+	switch expr := unwrapExpr(expr).(type) {
+	case *hclsyntax.BinaryOpExpr:
+		return vt.translateBinaryOp(expr)
+	case *hclsyntax.ConditionalExpr:
+		// 'var.x == null ? true : <condition>' is the lazy equivalent of the null check below
+		if whenNull, ok := literalValue(expr.TrueResult); ok && whenNull.RawEquals(cty.True) && vt.isNullCheck(expr.Condition) {
+			return vt.translate(expr.FalseResult)
+		}
+		return false
+	case *hclsyntax.FunctionCallExpr:
+		switch expr.Name {
+		case "can":
+			return vt.translateCan(expr)
+		case "contains":
+			return vt.translateContains(expr)
+		case "alltrue":
+			return vt.translateAllTrue(expr)
+		}
+	}
+	return false
+}
+
+func (vt *validationTranslator) translateBinaryOp(expr *hclsyntax.BinaryOpExpr) bool {
+	switch expr.Op {
+	case hclsyntax.OpLogicalAnd:
+		lhsOk := vt.translate(expr.LHS)
+		rhsOk := vt.translate(expr.RHS)
+		return lhsOk && rhsOk
+	case hclsyntax.OpLogicalOr:
+		// JSON Schema constraints don't apply to absent values, so 'var.x == null || <condition>' is just <condition>
+		if vt.isNullCheck(expr.LHS) {
+			return vt.translate(expr.RHS)
+		}
+		if vt.isNullCheck(expr.RHS) {
+			return vt.translate(expr.LHS)
+		}
+		return false
+	case hclsyntax.OpGreaterThan, hclsyntax.OpGreaterThanOrEqual, hclsyntax.OpLessThan, hclsyntax.OpLessThanOrEqual, hclsyntax.OpEqual:
+		return vt.translateComparison(expr)
+	}
+	return false
+}
+
+func (vt *validationTranslator) translateComparison(expr *hclsyntax.BinaryOpExpr) bool {
+	op := expr.Op
+	operand, limitExpr := expr.LHS, expr.RHS
+	limit, ok := literalValue(limitExpr)
+	if !ok {
+		// the literal may be on the left (1 <= var.x), so flip the comparison around
+		operand, limitExpr = expr.RHS, expr.LHS
+		limit, ok = literalValue(limitExpr)
+		op = flipComparison(op)
+	}
+	if !ok || limit.IsNull() {
+		return false
+	}
+
+	if limit.Type() != cty.Number {
+		// var.x == "foo"
+		subject := vt.resolve(operand)
+		if subject == nil || op != hclsyntax.OpEqual {
+			return false
+		}
+		subject.Const = ctyValueToInterface(limit)
+		return true
+	}
+
+	if call, isCall := unwrapExpr(operand).(*hclsyntax.FunctionCallExpr); isCall && call.Name == "length" && len(call.Args) == 1 {
+		subject := vt.resolve(call.Args[0])
+		if subject == nil {
+			return false
+		}
+		return applyLengthComparison(subject, op, limit)
+	}
+
+	subject := vt.resolve(operand)
+	if subject == nil {
+		return false
+	}
+	return applyNumericComparison(subject, op, limit)
+}
+
+func (vt *validationTranslator) translateCan(expr *hclsyntax.FunctionCallExpr) bool {
+	if len(expr.Args) != 1 {
+		return false
+	}
+	call, ok := unwrapExpr(expr.Args[0]).(*hclsyntax.FunctionCallExpr)
+	if !ok || call.Name != "regex" || len(call.Args) != 2 {
+		return false
+	}
+	pattern, patternOk := literalValue(call.Args[0])
+	if !patternOk || pattern.IsNull() || pattern.Type() != cty.String {
+		return false
+	}
+	subject := vt.resolve(call.Args[1])
+	if subject == nil {
+		return false
+	}
+	subject.Pattern = pattern.AsString()
+	return true
+}
+
+func (vt *validationTranslator) translateContains(expr *hclsyntax.FunctionCallExpr) bool {
+	if len(expr.Args) != 2 {
+		return false
+	}
+	values, ok := literalValue(expr.Args[0])
+	if !ok || values.IsNull() || !(values.Type().IsTupleType() || values.Type().IsListType() || values.Type().IsSetType()) {
+		return false
+	}
+	subject := vt.resolve(expr.Args[1])
+	if subject == nil {
+		return false
+	}
+	enum, isSlice := ctyValueToInterface(values).([]interface{})
+	if !isSlice {
+		return false
+	}
+	subject.Enum = enum
+	return true
+}
+
+func (vt *validationTranslator) translateAllTrue(expr *hclsyntax.FunctionCallExpr) bool {
+	if len(expr.Args) != 1 {
+		return false
+	}
+	forExpr, ok := unwrapExpr(expr.Args[0]).(*hclsyntax.ForExpr)
+	if !ok || forExpr.KeyExpr != nil || forExpr.CondExpr != nil {
+		return false
+	}
+	collection := vt.resolve(forExpr.CollExpr)
+	if collection == nil {
+		return false
+	}
+
+	var element *schema.Schema
+	if collection.Items != nil {
+		element = collection.Items
+	} else if addProps, isSchema := collection.AdditionalProperties.(*schema.Schema); isSchema {
+		element = addProps
+	}
+	if element == nil {
+		return false
+	}
+
+	vt.scope[forExpr.ValVar] = element
+	defer delete(vt.scope, forExpr.ValVar)
+
+	return vt.translate(forExpr.ValExpr)
+}
+
+// Find the schema node an expression refers to: either the variable itself (var.x), an attribute
+// within it (var.x.foo) or a loop variable introduced by a for expression
+func (vt *validationTranslator) resolve(expr hclsyntax.Expression) *schema.Schema {
+	// try(var.x.y, null) is used to safely reference attributes of values that may be null
+	if call, isCall := unwrapExpr(expr).(*hclsyntax.FunctionCallExpr); isCall && call.Name == "try" && len(call.Args) == 2 {
+		if fallback, literal := literalValue(call.Args[1]); literal && fallback.IsNull() {
+			return vt.resolve(call.Args[0])
+		}
+	}
+
+	traversalExpr, ok := unwrapExpr(expr).(*hclsyntax.ScopeTraversalExpr)
+	if !ok {
+		return nil
+	}
+	traversal := traversalExpr.Traversal
+
+	var current *schema.Schema
+	var rest hcl.Traversal
+	if traversal.RootName() == "var" {
+		if len(traversal) < 2 {
+			return nil
+		}
+		attr, isAttr := traversal[1].(hcl.TraverseAttr)
+		if !isAttr || attr.Name != vt.name {
+			return nil
+		}
+		current = vt.root
+		rest = traversal[2:]
+	} else {
+		current = vt.scope[traversal.RootName()]
+		rest = traversal[1:]
+	}
+
+	for _, step := range rest {
+		attr, isAttr := step.(hcl.TraverseAttr)
+		if !isAttr || current == nil || current.Properties == nil {
+			return nil
+		}
+		current, _ = current.Properties.Get(attr.Name)
+	}
+	return current
+}
+
+func (vt *validationTranslator) isNullCheck(expr hclsyntax.Expression) bool {
+	binary, ok := unwrapExpr(expr).(*hclsyntax.BinaryOpExpr)
+	if !ok || binary.Op != hclsyntax.OpEqual {
+		return false
+	}
+	isNull := func(operand hclsyntax.Expression) bool {
+		val, literal := literalValue(operand)
+		return literal && val.IsNull()
+	}
+	return (vt.resolve(binary.LHS) != nil && isNull(binary.RHS)) || (vt.resolve(binary.RHS) != nil && isNull(binary.LHS))
+}
+
+func applyNumericComparison(sch *schema.Schema, op *hclsyntax.Operation, limit cty.Value) bool {
+	number := json.Number(limit.AsBigFloat().Text('f', -1))
+	switch op {
+	case hclsyntax.OpGreaterThanOrEqual:
+		sch.Minimum = number
+	case hclsyntax.OpGreaterThan:
+		sch.ExclusiveMinimum = number
+	case hclsyntax.OpLessThanOrEqual:
+		sch.Maximum = number
+	case hclsyntax.OpLessThan:
+		sch.ExclusiveMaximum = number
+	default:
+		return false
+	}
+	return true
+}
+
+func applyLengthComparison(sch *schema.Schema, op *hclsyntax.Operation, limit cty.Value) bool {
+	bigLimit := limit.AsBigFloat()
+	if !bigLimit.IsInt() || bigLimit.Sign() < 0 {
+		return false
+	}
+	length, _ := bigLimit.Uint64()
+
+	var minimum, maximum *uint64
+	switch op {
+	case hclsyntax.OpGreaterThanOrEqual:
+		minimum = &length
+	case hclsyntax.OpGreaterThan:
+		length++
+		minimum = &length
+	case hclsyntax.OpLessThanOrEqual:
+		maximum = &length
+	case hclsyntax.OpLessThan:
+		if length == 0 {
+			return false
+		}
+		length--
+		maximum = &length
+	case hclsyntax.OpEqual:
+		minimum = &length
+		maximum = &length
+	default:
+		return false
+	}
+
+	schemaType, _ := sch.NullableType()
+	switch schemaType {
+	case "string":
+		sch.MinLength = coalesceLength(minimum, sch.MinLength)
+		sch.MaxLength = coalesceLength(maximum, sch.MaxLength)
+	case "array":
+		sch.MinItems = coalesceLength(minimum, sch.MinItems)
+		sch.MaxItems = coalesceLength(maximum, sch.MaxItems)
+	case "object":
+		sch.MinProperties = coalesceLength(minimum, sch.MinProperties)
+		sch.MaxProperties = coalesceLength(maximum, sch.MaxProperties)
+	default:
+		return false
+	}
+	return true
+}
+
+func coalesceLength(value, existing *uint64) *uint64 {
+	if value != nil {
+		return value
+	}
+	return existing
+}
+
+func flipComparison(op *hclsyntax.Operation) *hclsyntax.Operation {
+	switch op {
+	case hclsyntax.OpGreaterThan:
+		return hclsyntax.OpLessThan
+	case hclsyntax.OpGreaterThanOrEqual:
+		return hclsyntax.OpLessThanOrEqual
+	case hclsyntax.OpLessThan:
+		return hclsyntax.OpGreaterThan
+	case hclsyntax.OpLessThanOrEqual:
+		return hclsyntax.OpGreaterThanOrEqual
+	}
+	return op
+}
+
+// Evaluate an expression which doesn't reference anything (a literal, or a collection of literals)
+func literalValue(expr hclsyntax.Expression) (cty.Value, bool) {
+	if len(expr.Variables()) > 0 {
+		return cty.NilVal, false
+	}
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	return val, true
+}
+
+func unwrapExpr(expr hclsyntax.Expression) hclsyntax.Expression {
+	for {
+		switch wrapped := expr.(type) {
+		case *hclsyntax.ParenthesesExpr:
+			expr = wrapped.Expression
+		case *hclsyntax.TemplateWrapExpr:
+			expr = wrapped.Wrapped
+		default:
+			return expr
+		}
+	}
+}
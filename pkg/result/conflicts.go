@@ -0,0 +1,38 @@
+package result
+
+import (
+	"fmt"
+
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+)
+
+// ConflictDiagnostics reports the conflicts found flattening a schema as warnings, since the first definition is
+// used
+func ConflictDiagnostics(conflicts []schema.Conflict) []Diagnostic {
+	diags := []Diagnostic{}
+	for _, conflict := range conflicts {
+		diags = append(diags, Diagnostic{
+			Path:    conflict.Path,
+			Code:    "merge_conflict",
+			Message: fmt.Sprintf("conflicting definitions of '%s': %s", conflict.Path, conflict.Message),
+			Level:   Warning,
+		})
+	}
+	return diags
+}
+
+// RecursiveRefDiagnostics reports the recursive references left unresolved in a schema as warnings, since they
+// accept any value
+func RecursiveRefDiagnostics(refs []schema.RecursiveRef) []Diagnostic {
+	diags := []Diagnostic{}
+	for _, ref := range refs {
+		diags = append(diags, Diagnostic{
+			Path:          ref.Pointer,
+			Code:          "recursive_ref",
+			Message:       fmt.Sprintf("recursive $ref %q can't be expanded, so any value is accepted", ref.Ref),
+			Level:         Warning,
+			SchemaPointer: ref.Pointer,
+		})
+	}
+	return diags
+}
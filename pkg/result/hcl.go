@@ -0,0 +1,11 @@
+package result
+
+import hcl "github.com/hashicorp/hcl/v2"
+
+// HCLRange is the range of an HCL expression or block
+func HCLRange(rng hcl.Range) *Range {
+	return &Range{
+		Start: Position{Line: rng.Start.Line, Column: rng.Start.Column},
+		End:   Position{Line: rng.End.Line, Column: rng.End.Column},
+	}
+}
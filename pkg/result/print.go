@@ -8,14 +8,55 @@ import (
 )
 
 func (result *SchemaResult) PrettyDiags() string {
+	return prettyDiags(result.Diags)
+}
+
+func (result *CodeResult) PrettyDiags() string {
+	return prettyDiags(result.Diags)
+}
+
+func prettyDiags(diags []Diagnostic) string {
+	output := ""
+	for _, diag := range diags {
+		levelString := prettylogs.Orange("WARNING")
+		if diag.Level == Error {
+			levelString = prettylogs.Red("ERROR")
+		}
+		if location := diag.Location(); location != "" {
+			output += fmt.Sprintf("Airlock %s: %s: %s\n", levelString, location, diag.Message)
+		} else {
+			output += fmt.Sprintf("Airlock %s: %s\n", levelString, diag.Message)
+		}
+	}
+	return output
+}
+
+// Pretty renders diagnostics for a terminal, one per line followed by a count, or the clean message if there are
+// none. Diagnostics are located in their file if they have one, otherwise by their path
+func Pretty(diags []Diagnostic, clean string) string {
+	if len(diags) == 0 {
+		return clean + "\n"
+	}
+
 	output := ""
-	for _, diag := range result.Diags {
+	errors := 0
+	for _, diag := range diags {
 		levelString := prettylogs.Orange("WARNING")
 		if diag.Level == Error {
 			levelString = prettylogs.Red("ERROR")
+			errors++
+		}
+		location := diag.Location()
+		if location == "" {
+			path := diag.Path
+			if path == "" {
+				path = "(root)"
+			}
+			location = prettylogs.Underline(path).String()
 		}
-		output += fmt.Sprintf("Airlock %s: %s\n", levelString, diag.Message)
+		output += fmt.Sprintf("%s %s %s: %s\n", levelString, diag.Code, location, diag.Message)
 	}
+	output += fmt.Sprintf("%d problem(s), %d error(s)\n", len(diags), errors)
 	return output
 }
 
@@ -29,3 +70,42 @@ func (result *SchemaResult) PrettySchema() string {
 	}
 	return string(bytes)
 }
+
+// JSON renders the schema and its diagnostics as a single JSON object
+func (result *SchemaResult) JSON() (string, error) {
+	return marshalResult(map[string]any{
+		"schema":      result.Schema,
+		"diagnostics": orEmpty(result.Diags),
+	})
+}
+
+// JSON renders the code and its diagnostics as a single JSON object
+func (result *CodeResult) JSON() (string, error) {
+	return marshalResult(map[string]any{
+		"code":        string(result.Code),
+		"diagnostics": orEmpty(result.Diags),
+	})
+}
+
+// DiagnosticsJSON renders diagnostics on their own, for commands that don't produce a schema or code
+func DiagnosticsJSON(diags []Diagnostic) (string, error) {
+	return marshalResult(map[string]any{
+		"diagnostics": orEmpty(diags),
+	})
+}
+
+func marshalResult(value map[string]any) (string, error) {
+	bytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(bytes) + "\n", nil
+}
+
+// so no diagnostics is an empty array rather than null
+func orEmpty(diags []Diagnostic) []Diagnostic {
+	if diags == nil {
+		return []Diagnostic{}
+	}
+	return diags
+}
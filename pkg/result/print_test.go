@@ -0,0 +1,23 @@
+package result_test
+
+import (
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPretty(t *testing.T) {
+	require.Equal(t, "All good\n", result.Pretty(nil, "All good"))
+
+	diags := []result.Diagnostic{
+		{Path: "name", Code: "missing_description", Message: "name has no description", Level: result.Warning},
+		{Code: "type_mismatch", Message: "size is a number", Level: result.Error, File: "variables.tf", Range: &result.Range{Start: result.Position{Line: 3, Column: 5}}},
+		{Code: "unconstrained_type", Message: "anything goes", Level: result.Warning},
+	}
+	want := "WARNING missing_description name: name has no description\n" +
+		"ERROR type_mismatch variables.tf:3:5: size is a number\n" +
+		"WARNING unconstrained_type (root): anything goes\n" +
+		"3 problem(s), 1 error(s)\n"
+	require.Equal(t, want, result.Pretty(diags, "All good"))
+}
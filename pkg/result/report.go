@@ -0,0 +1,213 @@
+package result
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+
+	"github.com/massdriver-cloud/airlock/pkg/version"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	airlockURI   = "https://github.com/massdriver-cloud/airlock"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      Severity          `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIF columns start at 1 and the end column is exclusive, same as Range. Unknown columns are left out
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// SARIF renders diagnostics as a SARIF 2.1.0 log, for code scanning tools. Each diagnostic code is a rule
+func SARIF(diags []Diagnostic) (string, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "airlock",
+			Version:        version.AirlockVersion(),
+			InformationURI: airlockURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	seenRules := map[string]bool{}
+	for _, diag := range diags {
+		if !seenRules[diag.Code] {
+			seenRules[diag.Code] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: diag.Code})
+		}
+		run.Results = append(run.Results, sarifResultFromDiagnostic(diag))
+	}
+
+	bytes, err := json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(bytes) + "\n", nil
+}
+
+func sarifResultFromDiagnostic(diag Diagnostic) sarifResult {
+	res := sarifResult{
+		RuleID:  diag.Code,
+		Level:   diag.Level,
+		Message: sarifMessage{Text: diag.Message},
+	}
+	if diag.SchemaPointer != "" {
+		res.Properties = map[string]string{"schemaPointer": diag.SchemaPointer}
+	}
+
+	location := sarifLocation{}
+	if diag.File != "" {
+		location.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(diag.File)},
+		}
+		if diag.Range != nil {
+			location.PhysicalLocation.Region = &sarifRegion{
+				StartLine:   diag.Range.Start.Line,
+				StartColumn: diag.Range.Start.Column,
+				EndLine:     diag.Range.End.Line,
+				EndColumn:   diag.Range.End.Column,
+			}
+		}
+	}
+	if diag.Path != "" {
+		location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: diag.Path}}
+	}
+	if location.PhysicalLocation != nil || location.LogicalLocations != nil {
+		res.Locations = []sarifLocation{location}
+	}
+	return res
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit renders diagnostics as a JUnit XML report with a failed test case per diagnostic, for CI test report
+// widgets. With no diagnostics the suite has a single passing test case, so it doesn't show up as empty
+func JUnit(suite string, diags []Diagnostic) (string, error) {
+	testSuite := junitTestSuite{Name: suite, TestCases: []junitTestCase{}}
+
+	for _, diag := range diags {
+		className := diag.File
+		if className == "" {
+			className = suite
+		}
+		name := diag.Code
+		if diag.Path != "" {
+			name = fmt.Sprintf("%s: %s", diag.Code, diag.Path)
+		}
+		text := diag.Message
+		if location := diag.Location(); location != "" {
+			text = fmt.Sprintf("%s: %s", location, diag.Message)
+		}
+		testSuite.TestCases = append(testSuite.TestCases, junitTestCase{
+			Name:      name,
+			ClassName: className,
+			Failure:   &junitFailure{Message: diag.Message, Type: string(diag.Level), Text: text},
+		})
+	}
+	testSuite.Failures = len(testSuite.TestCases)
+
+	if len(testSuite.TestCases) == 0 {
+		testSuite.TestCases = append(testSuite.TestCases, junitTestCase{Name: suite, ClassName: suite})
+	}
+	testSuite.Tests = len(testSuite.TestCases)
+
+	report := junitTestSuites{
+		Name:     "airlock",
+		Tests:    testSuite.Tests,
+		Failures: testSuite.Failures,
+		Suites:   []junitTestSuite{testSuite},
+	}
+	bytes, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(bytes) + "\n", nil
+}
@@ -0,0 +1,162 @@
+package result_test
+
+import (
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/stretchr/testify/require"
+)
+
+var reportDiags = []result.Diagnostic{
+	{
+		Path:    "any",
+		Code:    "unconstrained_type",
+		Message: "unconstrained type in field 'any' from OpenTofu/Terraform 'any'",
+		Level:   result.Warning,
+		File:    "variables.tf",
+		Range: &result.Range{
+			Start: result.Position{Line: 3, Column: 10},
+			End:   result.Position{Line: 3, Column: 13},
+		},
+		SchemaPointer: "/properties/any",
+	},
+	{
+		Path:    "size",
+		Code:    "invalid_value",
+		Message: "failed to parse integer",
+		Level:   result.Error,
+		File:    "values.yaml",
+		Range: &result.Range{
+			Start: result.Position{Line: 7},
+			End:   result.Position{Line: 7},
+		},
+	},
+	{
+		Code:    "module_load_error",
+		Message: "failed to load module",
+		Level:   result.Error,
+	},
+}
+
+func TestSARIF(t *testing.T) {
+	type testData struct {
+		name  string
+		diags []result.Diagnostic
+		want  string
+	}
+	tests := []testData{
+		{
+			name:  "no diagnostics",
+			diags: []result.Diagnostic{},
+			want: `{
+				"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+				"version": "2.1.0",
+				"runs": [{
+					"tool": {"driver": {"name": "airlock", "version": "unknown", "informationUri": "https://github.com/massdriver-cloud/airlock", "rules": []}},
+					"results": []
+				}]
+			}`,
+		},
+		{
+			name:  "diagnostics",
+			diags: reportDiags,
+			want: `{
+				"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+				"version": "2.1.0",
+				"runs": [{
+					"tool": {"driver": {"name": "airlock", "version": "unknown", "informationUri": "https://github.com/massdriver-cloud/airlock", "rules": [
+						{"id": "unconstrained_type"},
+						{"id": "invalid_value"},
+						{"id": "module_load_error"}
+					]}},
+					"results": [
+						{
+							"ruleId": "unconstrained_type",
+							"level": "warning",
+							"message": {"text": "unconstrained type in field 'any' from OpenTofu/Terraform 'any'"},
+							"locations": [{
+								"physicalLocation": {
+									"artifactLocation": {"uri": "variables.tf"},
+									"region": {"startLine": 3, "startColumn": 10, "endLine": 3, "endColumn": 13}
+								},
+								"logicalLocations": [{"fullyQualifiedName": "any"}]
+							}],
+							"properties": {"schemaPointer": "/properties/any"}
+						},
+						{
+							"ruleId": "invalid_value",
+							"level": "error",
+							"message": {"text": "failed to parse integer"},
+							"locations": [{
+								"physicalLocation": {
+									"artifactLocation": {"uri": "values.yaml"},
+									"region": {"startLine": 7, "endLine": 7}
+								},
+								"logicalLocations": [{"fullyQualifiedName": "size"}]
+							}]
+						},
+						{
+							"ruleId": "module_load_error",
+							"level": "error",
+							"message": {"text": "failed to load module"}
+						}
+					]
+				}]
+			}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := result.SARIF(tc.diags)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.want, got)
+		})
+	}
+}
+
+func TestJUnit(t *testing.T) {
+	type testData struct {
+		name  string
+		diags []result.Diagnostic
+		want  string
+	}
+	tests := []testData{
+		{
+			name:  "no diagnostics",
+			diags: []result.Diagnostic{},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="airlock" tests="1" failures="0">
+  <testsuite name="airlock lint" tests="1" failures="0" errors="0">
+    <testcase name="airlock lint" classname="airlock lint"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name:  "diagnostics",
+			diags: reportDiags,
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="airlock" tests="3" failures="3">
+  <testsuite name="airlock lint" tests="3" failures="3" errors="0">
+    <testcase name="unconstrained_type: any" classname="variables.tf">
+      <failure message="unconstrained type in field &#39;any&#39; from OpenTofu/Terraform &#39;any&#39;" type="warning">variables.tf:3:10: unconstrained type in field &#39;any&#39; from OpenTofu/Terraform &#39;any&#39;</failure>
+    </testcase>
+    <testcase name="invalid_value: size" classname="values.yaml">
+      <failure message="failed to parse integer" type="error">values.yaml:7: failed to parse integer</failure>
+    </testcase>
+    <testcase name="module_load_error" classname="airlock lint">
+      <failure message="failed to load module" type="error">failed to load module</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := result.JUnit("airlock lint", tc.diags)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
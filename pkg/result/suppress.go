@@ -0,0 +1,144 @@
+package result
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// IgnoreDirective is the comment that suppresses diagnostics in a source file
+const IgnoreDirective = "airlock:ignore"
+
+var ignoreDirectivePattern = regexp.MustCompile(IgnoreDirective + `(?:[ \t]+([a-z0-9_]+(?:[ \t]*,[ \t]*[a-z0-9_]+)*))?`)
+
+// Suppress drops the diagnostics with one of the given codes, along with the ones suppressed by an
+// 'airlock:ignore code1, code2' comment on the line they start on or the line before, or on the first line of a
+// block enclosing them or the line before that. A comment without codes suppresses every diagnostic there
+func Suppress(diags []Diagnostic, codes []string) []Diagnostic {
+	files := map[string][]string{}
+	kept := []Diagnostic{}
+	for _, diag := range diags {
+		if slices.Contains(codes, diag.Code) || suppressedInline(diag, files) {
+			continue
+		}
+		kept = append(kept, diag)
+	}
+	return kept
+}
+
+func suppressedInline(diag Diagnostic, files map[string][]string) bool {
+	if diag.File == "" || diag.Range == nil || diag.Range.Start.Line == 0 {
+		return false
+	}
+
+	lines, read := files[diag.File]
+	if !read {
+		// an unreadable file just can't suppress anything
+		if contents, err := os.ReadFile(diag.File); err == nil {
+			lines = strings.Split(string(contents), "\n")
+		}
+		files[diag.File] = lines
+	}
+
+	// lines are numbered from 1, so this is the line the diagnostic starts on
+	start := diag.Range.Start.Line - 1
+	if start >= len(lines) {
+		return false
+	}
+	for _, blockStart := range append([]int{start}, enclosingBlocks(diag.File, lines, start)...) {
+		for _, index := range []int{blockStart, blockStart - 1} {
+			if index >= 0 && ignoresCode(lines[index], diag.Code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// The first lines of the blocks enclosing a line, innermost first. YAML blocks are found by indentation, other
+// files by braces
+func enclosingBlocks(path string, lines []string, index int) []int {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return enclosingIndentedBlocks(lines, index)
+	default:
+		return enclosingBracedBlocks(lines, index)
+	}
+}
+
+func enclosingIndentedBlocks(lines []string, index int) []int {
+	blocks := []int{}
+	indent := len(lines[index]) - len(strings.TrimLeft(lines[index], " "))
+	for current := index - 1; current >= 0 && indent > 0; current-- {
+		trimmed := strings.TrimLeft(lines[current], " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if lineIndent := len(lines[current]) - len(trimmed); lineIndent < indent {
+			blocks = append(blocks, current)
+			indent = lineIndent
+		}
+	}
+	return blocks
+}
+
+func enclosingBracedBlocks(lines []string, index int) []int {
+	blocks := []int{}
+	// closing braces found so far that haven't been matched with their opening brace
+	depth := 0
+	for current := index - 1; current >= 0; current-- {
+		code := withoutStringsAndComments(lines[current])
+		for position := len(code) - 1; position >= 0; position-- {
+			switch {
+			case code[position] == '}':
+				depth++
+			case code[position] == '{' && depth > 0:
+				depth--
+			case code[position] == '{' && (len(blocks) == 0 || blocks[len(blocks)-1] != current):
+				blocks = append(blocks, current)
+			}
+		}
+	}
+	return blocks
+}
+
+// The line without quoted strings and # or // comments, so the braces in them aren't counted
+func withoutStringsAndComments(line string) string {
+	code := strings.Builder{}
+	var quote rune
+	escaped := false
+	for index, char := range line {
+		switch {
+		case quote != 0 && escaped:
+			escaped = false
+		case quote != 0 && char == '\\':
+			escaped = true
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote != 0:
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '#' || strings.HasPrefix(line[index:], "//"):
+			return code.String()
+		default:
+			code.WriteRune(char)
+		}
+	}
+	return code.String()
+}
+
+func ignoresCode(line, code string) bool {
+	for _, match := range ignoreDirectivePattern.FindAllStringSubmatch(line, -1) {
+		if match[1] == "" {
+			return true
+		}
+		for _, ignored := range strings.Split(match[1], ",") {
+			if strings.TrimSpace(ignored) == code {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package result_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppress(t *testing.T) {
+	source := `variable "any" {
+  type = any # airlock:ignore unconstrained_type
+}
+
+# airlock:ignore untranslated_validation, unconstrained_type
+variable "cidr" {
+  type = any
+}
+
+variable "all" { # airlock:ignore
+  type = any
+}
+
+# airlock:ignore unknown_type
+variable "nested" {
+  type = object({
+    name = optional(string, "{")
+  })
+  validation {
+    condition = true
+  }
+}
+
+variable "after" {
+  type = any
+}
+`
+	values := `# airlock:ignore unknown_type
+resources:
+  limits:
+    cpu: null
+other: null
+`
+	dir := t.TempDir()
+	file := filepath.Join(dir, "variables.tf")
+	require.NoError(t, os.WriteFile(file, []byte(source), 0o600))
+	valuesFile := filepath.Join(dir, "values.yaml")
+	require.NoError(t, os.WriteFile(valuesFile, []byte(values), 0o600))
+
+	atFile := func(file, code string, line int) result.Diagnostic {
+		return result.Diagnostic{
+			Code:  code,
+			Level: result.Warning,
+			File:  file,
+			Range: &result.Range{Start: result.Position{Line: line}, End: result.Position{Line: line}},
+		}
+	}
+	at := func(code string, line int) result.Diagnostic {
+		return atFile(file, code, line)
+	}
+
+	type testData struct {
+		name  string
+		diags []result.Diagnostic
+		codes []string
+		want  []result.Diagnostic
+	}
+	tests := []testData{
+		{
+			name:  "same line",
+			diags: []result.Diagnostic{at("unconstrained_type", 2), at("unknown_type", 2)},
+			want:  []result.Diagnostic{at("unknown_type", 2)},
+		},
+		{
+			name:  "line before",
+			diags: []result.Diagnostic{at("untranslated_validation", 6), at("unknown_type", 7)},
+			want:  []result.Diagnostic{at("unknown_type", 7)},
+		},
+		{
+			name:  "enclosing block",
+			diags: []result.Diagnostic{at("unconstrained_type", 7), at("unknown_type", 20), at("unconstrained_type", 20), at("unknown_type", 25)},
+			want:  []result.Diagnostic{at("unconstrained_type", 20), at("unknown_type", 25)},
+		},
+		{
+			name:  "enclosing yaml block",
+			diags: []result.Diagnostic{atFile(valuesFile, "unknown_type", 4), atFile(valuesFile, "unknown_type", 5)},
+			want:  []result.Diagnostic{atFile(valuesFile, "unknown_type", 5)},
+		},
+		{
+			name:  "all codes",
+			diags: []result.Diagnostic{at("unconstrained_type", 10), at("unconstrained_type", 11)},
+			want:  []result.Diagnostic{},
+		},
+		{
+			name:  "codes",
+			diags: []result.Diagnostic{at("unconstrained_type", 7), {Code: "module_load_error", Level: result.Error}},
+			codes: []string{"unconstrained_type"},
+			want:  []result.Diagnostic{{Code: "module_load_error", Level: result.Error}},
+		},
+		{
+			name:  "missing file",
+			diags: []result.Diagnostic{{Code: "unknown_type", File: "missing.yaml", Range: &result.Range{Start: result.Position{Line: 1}}}},
+			want:  []result.Diagnostic{{Code: "unknown_type", File: "missing.yaml", Range: &result.Range{Start: result.Position{Line: 1}}}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := result.Suppress(tc.diags, tc.codes)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
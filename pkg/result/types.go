@@ -1,10 +1,23 @@
 package result
 
-import "github.com/massdriver-cloud/airlock/pkg/schema"
+import (
+	"fmt"
+
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+)
 
 type SchemaResult struct {
 	Schema *schema.Schema
 	Diags  []Diagnostic
+	// Where the properties of the schema are declared in the source, keyed by schema pointer. Converters only record
+	// the properties they know the location of
+	Locations map[string]Location
+}
+
+// Location is where something is declared in a source file
+type Location struct {
+	File  string
+	Range *Range
 }
 
 type CodeResult struct {
@@ -20,8 +33,38 @@ const (
 )
 
 type Diagnostic struct {
-	Path    string
-	Code    string
-	Message string
-	Level   Severity
+	Path    string   `json:"path"`
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Level   Severity `json:"level"`
+	// The source file the diagnostic is about and where in it, if known
+	File  string `json:"file,omitempty"`
+	Range *Range `json:"range,omitempty"`
+	// JSON pointer to the schema the diagnostic is about in the generated schema, if any
+	SchemaPointer string `json:"schemaPointer,omitempty"`
+}
+
+// Range is a span of a source file. Lines and columns start at 1 and the end is exclusive. A column of 0 means
+// only the line is known
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+// Location formats the file and start of the range like compilers do (main.tf:12:3), so editors and terminals
+// can link to it
+func (diag Diagnostic) Location() string {
+	location := diag.File
+	if diag.Range != nil {
+		location += fmt.Sprintf(":%d", diag.Range.Start.Line)
+		if diag.Range.Start.Column > 0 {
+			location += fmt.Sprintf(":%d", diag.Range.Start.Column)
+		}
+	}
+	return location
 }
@@ -0,0 +1,21 @@
+package result
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ValidationDiagnostics reports the errors from validating a document against a schema, so they can be rendered
+// the same way as the diagnostics from the converters
+func ValidationDiagnostics(documentPath string, errs []gojsonschema.ResultError) []Diagnostic {
+	diags := []Diagnostic{}
+	for _, err := range errs {
+		diags = append(diags, Diagnostic{
+			Path:    err.Field(),
+			Code:    err.Type(),
+			Message: err.Description(),
+			Level:   Error,
+			File:    documentPath,
+		})
+	}
+	return diags
+}
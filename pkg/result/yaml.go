@@ -0,0 +1,55 @@
+package result
+
+import (
+	yaml "gopkg.in/yaml.v3"
+)
+
+// YAMLRange is the range of a node in a YAML file, given the lines of the file. yaml.v3 only records where nodes
+// start, so the end is found in the source, and only for scalars on a single line. Columns count characters, not
+// bytes
+func YAMLRange(lines []string, node *yaml.Node) *Range {
+	start := Position{Line: node.Line, Column: node.Column}
+	end := start
+	if node.Kind == yaml.ScalarNode && node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 && node.Line <= len(lines) {
+		line := []rune(lines[node.Line-1])
+		if length := scalarLength(line[min(node.Column-1, len(line)):], node); length > 0 {
+			end.Column += length
+		}
+	}
+	return &Range{Start: start, End: end}
+}
+
+// The number of characters the scalar takes up at the start of the text, or 0 if it doesn't end on the same line.
+// Quoted scalars are found by their closing quote since escapes make them longer than their value
+func scalarLength(text []rune, node *yaml.Node) int {
+	switch {
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		for index := 1; index < len(text); index++ {
+			switch text[index] {
+			case '\\':
+				index++
+			case '"':
+				return index + 1
+			}
+		}
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		for index := 1; index < len(text); index++ {
+			if text[index] != '\'' {
+				continue
+			}
+			// a quote is escaped by doubling it
+			if index+1 < len(text) && text[index+1] == '\'' {
+				index++
+				continue
+			}
+			return index + 1
+		}
+	default:
+		// plain scalars are written as they are, unless they're folded over several lines
+		value := []rune(node.Value)
+		if len(value) <= len(text) && string(text[:len(value)]) == node.Value {
+			return len(value)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package result_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestYAMLRange(t *testing.T) {
+	type testData struct {
+		name     string
+		document string
+		want     *result.Range
+	}
+	tests := []testData{
+		{
+			name:     "plain",
+			document: "name: test",
+			want:     &result.Range{Start: result.Position{Line: 1, Column: 7}, End: result.Position{Line: 1, Column: 11}},
+		},
+		{
+			name:     "quoted",
+			document: `name: "test"`,
+			want:     &result.Range{Start: result.Position{Line: 1, Column: 7}, End: result.Position{Line: 1, Column: 13}},
+		},
+		{
+			name:     "escaped double quote",
+			document: `name: "a\"b"`,
+			want:     &result.Range{Start: result.Position{Line: 1, Column: 7}, End: result.Position{Line: 1, Column: 13}},
+		},
+		{
+			name:     "escaped single quote",
+			document: `name: 'it''s'`,
+			want:     &result.Range{Start: result.Position{Line: 1, Column: 7}, End: result.Position{Line: 1, Column: 14}},
+		},
+		{
+			name:     "folded plain",
+			document: "name: multiple\n  lines\n",
+			want:     &result.Range{Start: result.Position{Line: 1, Column: 7}, End: result.Position{Line: 1, Column: 7}},
+		},
+		{
+			name:     "multi-byte characters",
+			document: "name: héllo wörld",
+			want:     &result.Range{Start: result.Position{Line: 1, Column: 7}, End: result.Position{Line: 1, Column: 18}},
+		},
+		{
+			name:     "block",
+			document: "name: |\n  multiple\n  lines\n",
+			want:     &result.Range{Start: result.Position{Line: 1, Column: 7}, End: result.Position{Line: 1, Column: 7}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var document yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tc.document), &document))
+
+			value := document.Content[0].Content[1]
+			require.Equal(t, tc.want, result.YAMLRange(strings.Split(tc.document, "\n"), value))
+		})
+	}
+}
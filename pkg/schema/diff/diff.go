@@ -0,0 +1,438 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Change is a difference between two versions of a schema. A change is breaking if a document that was valid
+// against the old schema may not be valid against the new one
+type Change struct {
+	Path     string `json:"path"`
+	Kind     Kind   `json:"kind"`
+	Breaking bool   `json:"breaking"`
+	Message  string `json:"message"`
+}
+
+// Compare two versions of a schema, reporting added, removed and changed properties and constraints
+func Compare(before, after *schema.Schema) []Change {
+	c := comparer{changes: []Change{}}
+	c.compare("", before, after, false)
+	return c.changes
+}
+
+// HasBreaking returns true if any of the changes is breaking
+func HasBreaking(changes []Change) bool {
+	return slices.ContainsFunc(changes, func(change Change) bool { return change.Breaking })
+}
+
+// Diagnostics turns the changes into diagnostics, so they can fail a command and be written to reports like any
+// other: breaking changes are errors and the others warnings
+func Diagnostics(changes []Change) []result.Diagnostic {
+	diags := []result.Diagnostic{}
+	for _, change := range changes {
+		diag := result.Diagnostic{
+			Path:    change.Path,
+			Code:    "non_breaking_change",
+			Message: change.Message,
+			Level:   result.Warning,
+		}
+		if change.Breaking {
+			diag.Code = "breaking_change"
+			diag.Level = result.Error
+		}
+		diags = append(diags, diag)
+	}
+	return diags
+}
+
+type comparer struct {
+	changes []Change
+}
+
+func (c *comparer) add(path string, kind Kind, breaking bool, format string, args ...any) {
+	c.changes = append(c.changes, Change{
+		Path:     path,
+		Kind:     kind,
+		Breaking: breaking,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparer) compare(path string, before, after *schema.Schema, required bool) {
+	if before.Boolean != nil || after.Boolean != nil {
+		c.compareBoolean(path, before, after)
+		return
+	}
+
+	c.compareTypes(path, before, after)
+	c.compareEnums(path, before, after)
+	c.compareValue(path, "const", before.Const, after.Const)
+	c.compareValue(path, "pattern", nilIfEmpty(before.Pattern), nilIfEmpty(after.Pattern))
+	c.compareLimits(path, before, after)
+	c.compareDefaults(path, before, after, required)
+	c.compareProperties(path, before, after)
+	c.compareAdditionalProperties(path, before, after)
+
+	c.comparePrefixItems(path, before, after)
+	// missing items allow anything
+	if before.Items != nil || after.Items != nil {
+		c.compare(path+"[*]", orEmpty(before.Items), orEmpty(after.Items), false)
+	}
+
+	// every allOf branch has to match, so more branches are more constraints. anyOf is the opposite, and oneOf breaks
+	// either way since a value can stop matching or start matching more than one branch
+	c.compareBranches(path, "allOf", before.AllOf, after.AllOf, true, false)
+	c.compareBranches(path, "anyOf", before.AnyOf, after.AnyOf, false, true)
+	c.compareBranches(path, "oneOf", before.OneOf, after.OneOf, true, true)
+}
+
+// Tuples are compared element by element. Changing the length moves elements between prefixItems and items, so
+// it's breaking
+func (c *comparer) comparePrefixItems(path string, before, after *schema.Schema) {
+	if len(before.PrefixItems) != len(after.PrefixItems) {
+		c.add(path, Changed, true, "tuple length changed from %d to %d", len(before.PrefixItems), len(after.PrefixItems))
+	}
+	for index := range min(len(before.PrefixItems), len(after.PrefixItems)) {
+		c.compare(fmt.Sprintf("%s[%d]", path, index), before.PrefixItems[index], after.PrefixItems[index], false)
+	}
+}
+
+// Branches are compared by position, branches only in one of the schemas are added or removed. The properties of
+// the branches are already compared with the expanded properties of the schema, so they're left out here
+func (c *comparer) compareBranches(path, keyword string, before, after []*schema.Schema, addedBreaks, removedBreaks bool) {
+	for index := range max(len(before), len(after)) {
+		branchPath := propertyPath(path, fmt.Sprintf("%s[%d]", keyword, index))
+		switch {
+		case index >= len(before):
+			c.add(branchPath, Added, addedBreaks, "%s branch added", keyword)
+		case index >= len(after):
+			c.add(branchPath, Removed, removedBreaks, "%s branch removed", keyword)
+		default:
+			c.compare(branchPath, withoutProperties(before[index]), withoutProperties(after[index]), false)
+		}
+	}
+}
+
+func withoutProperties(sch *schema.Schema) *schema.Schema {
+	if sch.Boolean != nil {
+		return sch
+	}
+	copied := *sch
+	copied.Properties, copied.Required = nil, nil
+	return &copied
+}
+
+// The property as the schema declares it, so its own allOf/anyOf/oneOf can be compared branch by branch. Properties
+// only declared in branches of the schema fall back to their expanded definition
+func declared(sch *schema.Schema, key string, expanded *schema.Schema) *schema.Schema {
+	if sch.Properties == nil {
+		return expanded
+	}
+	if prop, exists := sch.Properties.Get(key); exists {
+		return prop
+	}
+	return expanded
+}
+
+func orEmpty(sch *schema.Schema) *schema.Schema {
+	if sch == nil {
+		return new(schema.Schema)
+	}
+	return sch
+}
+
+// true (anything) and false (nothing) schemas
+func (c *comparer) compareBoolean(path string, before, after *schema.Schema) {
+	allowsAnything := func(sch *schema.Schema) bool {
+		return sch.Boolean == nil || *sch.Boolean
+	}
+	switch {
+	case allowsAnything(before) && !allowsAnything(after):
+		c.add(path, Changed, true, "no longer allows any value")
+	case !allowsAnything(before) && allowsAnything(after):
+		c.add(path, Changed, false, "now allows values")
+	}
+}
+
+func (c *comparer) compareTypes(path string, before, after *schema.Schema) {
+	beforeTypes := sortedTypes(before)
+	afterTypes := sortedTypes(after)
+	if slices.Equal(beforeTypes, afterTypes) {
+		return
+	}
+
+	// a type is still accepted if the new schema allows any type, the same type, or number where it was integer
+	narrowed := false
+	for _, t := range beforeTypes {
+		if !typeAllowed(t, afterTypes) {
+			narrowed = true
+		}
+	}
+	if len(beforeTypes) == 0 {
+		narrowed = true
+	}
+
+	c.add(path, Changed, narrowed, "type changed from %s to %s", formatTypes(beforeTypes), formatTypes(afterTypes))
+}
+
+func sortedTypes(sch *schema.Schema) []string {
+	types := slices.Clone(sch.TypeNames())
+	slices.Sort(types)
+	return types
+}
+
+func typeAllowed(t string, types []string) bool {
+	return len(types) == 0 || slices.Contains(types, t) || (t == "integer" && slices.Contains(types, "number"))
+}
+
+func formatTypes(types []string) string {
+	if len(types) == 0 {
+		return "any"
+	}
+	return strings.Join(types, "|")
+}
+
+func (c *comparer) compareEnums(path string, before, after *schema.Schema) {
+	switch {
+	case len(before.Enum) == 0 && len(after.Enum) == 0:
+		return
+	case len(before.Enum) == 0:
+		c.add(path, Added, true, "enum added: %s", formatValues(after.Enum))
+		return
+	case len(after.Enum) == 0:
+		c.add(path, Removed, false, "enum removed")
+		return
+	}
+
+	removed := missingValues(before.Enum, after.Enum)
+	if len(removed) > 0 {
+		c.add(path, Removed, true, "enum values removed: %s", formatValues(removed))
+	}
+	added := missingValues(after.Enum, before.Enum)
+	if len(added) > 0 {
+		c.add(path, Added, false, "enum values added: %s", formatValues(added))
+	}
+}
+
+// values in from that aren't in to
+func missingValues(from, to []any) []any {
+	missing := []any{}
+	for _, value := range from {
+		if !slices.ContainsFunc(to, func(other any) bool { return reflect.DeepEqual(value, other) }) {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+// Keywords that constrain a value to match something (const, pattern). Adding or changing one can reject
+// previously valid values, removing one can't
+func (c *comparer) compareValue(path, keyword string, before, after any) {
+	beforeSet := before != nil
+	afterSet := after != nil
+	switch {
+	case !beforeSet && afterSet:
+		c.add(path, Added, true, "%s added: %s", keyword, formatValue(after))
+	case beforeSet && !afterSet:
+		c.add(path, Removed, false, "%s removed", keyword)
+	case beforeSet && afterSet && !reflect.DeepEqual(before, after):
+		c.add(path, Changed, true, "%s changed from %s to %s", keyword, formatValue(before), formatValue(after))
+	}
+}
+
+type limit struct {
+	keyword string
+	before  *float64
+	after   *float64
+	// lower bounds (minimum, minLength) tighten when they go up, upper bounds when they go down
+	lower bool
+}
+
+func (c *comparer) compareLimits(path string, before, after *schema.Schema) {
+	limits := []limit{
+		{"minimum", numberLimit(before.Minimum), numberLimit(after.Minimum), true},
+		{"exclusiveMinimum", numberLimit(before.ExclusiveMinimum), numberLimit(after.ExclusiveMinimum), true},
+		{"maximum", numberLimit(before.Maximum), numberLimit(after.Maximum), false},
+		{"exclusiveMaximum", numberLimit(before.ExclusiveMaximum), numberLimit(after.ExclusiveMaximum), false},
+		{"minLength", lengthLimit(before.MinLength), lengthLimit(after.MinLength), true},
+		{"maxLength", lengthLimit(before.MaxLength), lengthLimit(after.MaxLength), false},
+		{"minItems", lengthLimit(before.MinItems), lengthLimit(after.MinItems), true},
+		{"maxItems", lengthLimit(before.MaxItems), lengthLimit(after.MaxItems), false},
+		{"minProperties", lengthLimit(before.MinProperties), lengthLimit(after.MinProperties), true},
+		{"maxProperties", lengthLimit(before.MaxProperties), lengthLimit(after.MaxProperties), false},
+	}
+
+	for _, l := range limits {
+		switch {
+		case l.before == nil && l.after == nil:
+			continue
+		case l.before == nil:
+			c.add(path, Added, true, "%s added: %s", l.keyword, formatFloat(*l.after))
+		case l.after == nil:
+			c.add(path, Removed, false, "%s removed", l.keyword)
+		case *l.before != *l.after:
+			raised := *l.after > *l.before
+			direction := "lowered"
+			if raised {
+				direction = "raised"
+			}
+			c.add(path, Changed, raised == l.lower, "%s %s from %s to %s", l.keyword, direction, formatFloat(*l.before), formatFloat(*l.after))
+		}
+	}
+}
+
+func numberLimit(value json.Number) *float64 {
+	if value == "" {
+		return nil
+	}
+	f, err := value.Float64()
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+func lengthLimit(value *uint64) *float64 {
+	if value == nil {
+		return nil
+	}
+	f := float64(*value)
+	return &f
+}
+
+// Defaults don't affect validation, except that a required property with a default can be left out
+func (c *comparer) compareDefaults(path string, before, after *schema.Schema, required bool) {
+	switch {
+	case before.Default == nil && after.Default != nil:
+		c.add(path, Added, false, "default added: %s", formatValue(after.Default))
+	case before.Default != nil && after.Default == nil:
+		c.add(path, Removed, required, "default removed")
+	case !reflect.DeepEqual(before.Default, after.Default):
+		c.add(path, Changed, false, "default changed from %s to %s", formatValue(before.Default), formatValue(after.Default))
+	}
+}
+
+func (c *comparer) compareProperties(path string, before, after *schema.Schema) {
+	beforeProperties, beforeRequired := expand(before)
+	afterProperties, afterRequired := expand(after)
+
+	for prop := beforeProperties.Oldest(); prop != nil; prop = prop.Next() {
+		propPath := propertyPath(path, prop.Key)
+		afterProp, exists := afterProperties.Get(prop.Key)
+		if !exists {
+			// documents that set a removed property are validated against additionalProperties instead
+			additionalSchema, isSchema := after.AdditionalProperties.(*schema.Schema)
+			c.add(propPath, Removed, after.AdditionalProperties == false, "property removed")
+			if isSchema {
+				c.compare(propPath, declared(before, prop.Key, prop.Value), additionalSchema, false)
+			}
+			continue
+		}
+
+		wasRequired := slices.Contains(beforeRequired, prop.Key)
+		isRequired := slices.Contains(afterRequired, prop.Key)
+		switch {
+		case !wasRequired && isRequired && afterProp.Default == nil:
+			c.add(propPath, Changed, true, "property is now required and has no default")
+		case !wasRequired && isRequired:
+			c.add(propPath, Changed, false, "property is now required")
+		case wasRequired && !isRequired:
+			c.add(propPath, Changed, false, "property is no longer required")
+		}
+
+		c.compare(propPath, declared(before, prop.Key, prop.Value), declared(after, prop.Key, afterProp), isRequired)
+	}
+
+	for prop := afterProperties.Oldest(); prop != nil; prop = prop.Next() {
+		if _, exists := beforeProperties.Get(prop.Key); exists {
+			continue
+		}
+		if slices.Contains(afterRequired, prop.Key) && prop.Value.Default == nil {
+			c.add(propertyPath(path, prop.Key), Added, true, "required property added without a default")
+		} else {
+			c.add(propertyPath(path, prop.Key), Added, false, "property added")
+		}
+		// documents could already set it, validated against additionalProperties
+		if additionalSchema, isSchema := before.AdditionalProperties.(*schema.Schema); isSchema {
+			c.compare(propertyPath(path, prop.Key), additionalSchema, declared(after, prop.Key, prop.Value), false)
+		}
+	}
+}
+
+// The properties and required properties of a schema, including the ones declared in allOf/anyOf/oneOf and
+// conditional branches
+func expand(sch *schema.Schema) (*orderedmap.OrderedMap[string, *schema.Schema], []string) {
+	flattened, _ := schema.Flatten(sch)
+	if flattened.Properties == nil {
+		return orderedmap.New[string, *schema.Schema](), flattened.Required
+	}
+	return flattened.Properties, flattened.Required
+}
+
+func (c *comparer) compareAdditionalProperties(path string, before, after *schema.Schema) {
+	beforeClosed := before.AdditionalProperties == false
+	afterClosed := after.AdditionalProperties == false
+	switch {
+	case !beforeClosed && afterClosed:
+		c.add(path, Changed, true, "additional properties are no longer allowed")
+	case beforeClosed && !afterClosed:
+		c.add(path, Changed, false, "additional properties are now allowed")
+	}
+
+	beforeSchema, beforeIsSchema := before.AdditionalProperties.(*schema.Schema)
+	afterSchema, afterIsSchema := after.AdditionalProperties.(*schema.Schema)
+	if beforeIsSchema && afterIsSchema {
+		c.compare(propertyPath(path, "*"), beforeSchema, afterSchema, false)
+	}
+}
+
+func propertyPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func nilIfEmpty(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func formatValues(values []any) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = formatValue(value)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func formatValue(value any) string {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(bytes)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,165 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/massdriver-cloud/airlock/pkg/schema/diff"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	type testData struct {
+		name   string
+		before string
+		after  string
+		want   []diff.Change
+	}
+	tests := []testData{
+		{
+			name:   "unchanged",
+			before: `{"properties": {"name": {"type": "string"}}}`,
+			after:  `{"properties": {"name": {"type": "string"}}}`,
+			want:   []diff.Change{},
+		},
+		{
+			name:   "required property added",
+			before: `{"properties": {"name": {"type": "string"}}}`,
+			after:  `{"required": ["name", "size"], "properties": {"name": {"type": "string"}, "size": {"type": "integer"}, "zone": {"type": "string"}}}`,
+			want: []diff.Change{
+				{Path: "name", Kind: diff.Changed, Breaking: true, Message: "property is now required and has no default"},
+				{Path: "size", Kind: diff.Added, Breaking: true, Message: "required property added without a default"},
+				{Path: "zone", Kind: diff.Added, Breaking: false, Message: "property added"},
+			},
+		},
+		{
+			name:   "required moved into allOf",
+			before: `{"required": ["name"], "properties": {"name": {"type": "string"}, "zone": {"type": "string"}}, "allOf": [{"required": ["zone"]}]}`,
+			after:  `{"properties": {"name": {"type": "string"}, "zone": {"type": "string"}}, "allOf": [{"required": ["name"]}]}`,
+			want: []diff.Change{
+				{Path: "zone", Kind: diff.Changed, Breaking: false, Message: "property is no longer required"},
+			},
+		},
+		{
+			name:   "required property added with default",
+			before: `{"properties": {}}`,
+			after:  `{"required": ["size"], "properties": {"size": {"type": "integer", "default": 10}}}`,
+			want: []diff.Change{
+				{Path: "size", Kind: diff.Added, Breaking: false, Message: "property added"},
+			},
+		},
+		{
+			name:   "property removed",
+			before: `{"properties": {"name": {"type": "string"}, "size": {"type": "integer"}}}`,
+			after:  `{"additionalProperties": false, "properties": {"name": {"type": "string"}}}`,
+			want: []diff.Change{
+				{Path: "size", Kind: diff.Removed, Breaking: true, Message: "property removed"},
+				{Path: "", Kind: diff.Changed, Breaking: true, Message: "additional properties are no longer allowed"},
+			},
+		},
+		{
+			name:   "types",
+			before: `{"properties": {"narrowed": {"type": "number"}, "widened": {"type": "integer"}, "nullable": {"type": "string"}, "typed": {}}}`,
+			after:  `{"properties": {"narrowed": {"type": "integer"}, "widened": {"type": "number"}, "nullable": {"type": ["string", "null"]}, "typed": {"type": "string"}}}`,
+			want: []diff.Change{
+				{Path: "narrowed", Kind: diff.Changed, Breaking: true, Message: "type changed from number to integer"},
+				{Path: "widened", Kind: diff.Changed, Breaking: false, Message: "type changed from integer to number"},
+				{Path: "nullable", Kind: diff.Changed, Breaking: false, Message: "type changed from string to null|string"},
+				{Path: "typed", Kind: diff.Changed, Breaking: true, Message: "type changed from any to string"},
+			},
+		},
+		{
+			name:   "enums",
+			before: `{"properties": {"size": {"enum": ["small", "medium", "large"]}}}`,
+			after:  `{"properties": {"size": {"enum": ["medium", "large", "xlarge"]}}}`,
+			want: []diff.Change{
+				{Path: "size", Kind: diff.Removed, Breaking: true, Message: `enum values removed: "small"`},
+				{Path: "size", Kind: diff.Added, Breaking: false, Message: `enum values added: "xlarge"`},
+			},
+		},
+		{
+			name:   "limits",
+			before: `{"properties": {"port": {"type": "integer", "minimum": 1, "maximum": 65535}, "name": {"type": "string", "maxLength": 10}}}`,
+			after:  `{"properties": {"port": {"type": "integer", "minimum": 0, "maximum": 1024}, "name": {"type": "string", "minLength": 3}}}`,
+			want: []diff.Change{
+				{Path: "port", Kind: diff.Changed, Breaking: false, Message: "minimum lowered from 1 to 0"},
+				{Path: "port", Kind: diff.Changed, Breaking: true, Message: "maximum lowered from 65535 to 1024"},
+				{Path: "name", Kind: diff.Added, Breaking: true, Message: "minLength added: 3"},
+				{Path: "name", Kind: diff.Removed, Breaking: false, Message: "maxLength removed"},
+			},
+		},
+		{
+			name:   "nested",
+			before: `{"properties": {"db": {"type": "object", "properties": {"tags": {"type": "array", "items": {"type": "string"}}}}}}`,
+			after:  `{"properties": {"db": {"type": "object", "properties": {"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}}}}}}`,
+			want: []diff.Change{
+				{Path: "db.tags[*]", Kind: diff.Added, Breaking: true, Message: `pattern added: "^[a-z]+$"`},
+			},
+		},
+		{
+			name:   "defaults",
+			before: `{"required": ["size", "zone"], "properties": {"size": {"type": "integer", "default": 10}, "zone": {"type": "string", "default": "a"}}}`,
+			after:  `{"required": ["size", "zone"], "properties": {"size": {"type": "integer", "default": 20}, "zone": {"type": "string"}}}`,
+			want: []diff.Change{
+				{Path: "size", Kind: diff.Changed, Breaking: false, Message: "default changed from 10 to 20"},
+				{Path: "zone", Kind: diff.Removed, Breaking: true, Message: "default removed"},
+			},
+		},
+		{
+			name:   "tuples",
+			before: `{"properties": {"endpoint": {"type": "array", "prefixItems": [{"type": "string"}, {"type": "string"}], "items": false}, "pair": {"type": "array", "prefixItems": [{"type": "string"}], "items": false}}}`,
+			after:  `{"properties": {"endpoint": {"type": "array", "prefixItems": [{"type": "string"}, {"type": "number"}], "items": false}, "pair": {"type": "array", "prefixItems": [{"type": "string"}, {"type": "string"}], "items": false}}}`,
+			want: []diff.Change{
+				{Path: "endpoint[1]", Kind: diff.Changed, Breaking: true, Message: "type changed from string to number"},
+				{Path: "pair", Kind: diff.Changed, Breaking: true, Message: "tuple length changed from 1 to 2"},
+			},
+		},
+		{
+			name:   "composition",
+			before: `{"properties": {"size": {"anyOf": [{"type": "string"}, {"type": "integer"}]}, "name": {"allOf": [{"type": "string"}]}, "zone": {"oneOf": [{"const": "a"}, {"const": "b"}]}}}`,
+			after:  `{"properties": {"size": {"anyOf": [{"type": "string", "maxLength": 5}]}, "name": {"allOf": [{"type": "string"}, {"minLength": 1}]}, "zone": {"oneOf": [{"const": "a"}, {"const": "c"}]}}}`,
+			want: []diff.Change{
+				{Path: "size.anyOf[0]", Kind: diff.Added, Breaking: true, Message: "maxLength added: 5"},
+				{Path: "size.anyOf[1]", Kind: diff.Removed, Breaking: true, Message: "anyOf branch removed"},
+				{Path: "name.allOf[1]", Kind: diff.Added, Breaking: true, Message: "allOf branch added"},
+				{Path: "zone.oneOf[1]", Kind: diff.Changed, Breaking: true, Message: `const changed from "b" to "c"`},
+			},
+		},
+		{
+			name:   "additionalProperties schema",
+			before: `{"properties": {"name": {"type": "string"}}, "additionalProperties": {"type": "string"}}`,
+			after:  `{"properties": {"size": {"type": "integer"}}, "additionalProperties": {"type": "string", "maxLength": 10}}`,
+			want: []diff.Change{
+				{Path: "name", Kind: diff.Removed, Breaking: false, Message: "property removed"},
+				{Path: "name", Kind: diff.Added, Breaking: true, Message: "maxLength added: 10"},
+				{Path: "size", Kind: diff.Added, Breaking: false, Message: "property added"},
+				{Path: "size", Kind: diff.Changed, Breaking: true, Message: "type changed from string to integer"},
+				{Path: "*", Kind: diff.Added, Breaking: true, Message: "maxLength added: 10"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var before, after schema.Schema
+			require.NoError(t, json.Unmarshal([]byte(tc.before), &before))
+			require.NoError(t, json.Unmarshal([]byte(tc.after), &after))
+
+			got := diff.Compare(&before, &after)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	changes := []diff.Change{
+		{Path: "size", Kind: diff.Changed, Breaking: true, Message: "maximum lowered from 10 to 5"},
+		{Path: "name", Kind: diff.Added, Breaking: false, Message: "property added"},
+	}
+	want := []result.Diagnostic{
+		{Path: "size", Code: "breaking_change", Message: "maximum lowered from 10 to 5", Level: result.Error},
+		{Path: "name", Code: "non_breaking_change", Message: "property added", Level: result.Warning},
+	}
+	require.Equal(t, want, diff.Diagnostics(changes))
+}
@@ -0,0 +1,31 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/massdriver-cloud/airlock/pkg/prettylogs"
+)
+
+// Pretty renders the changes for a terminal, one per line with breaking changes highlighted
+func Pretty(changes []Change) string {
+	if len(changes) == 0 {
+		return "No changes\n"
+	}
+
+	output := ""
+	breaking := 0
+	for _, change := range changes {
+		levelString := prettylogs.Green("NON-BREAKING")
+		if change.Breaking {
+			levelString = prettylogs.Red("BREAKING")
+			breaking++
+		}
+		path := change.Path
+		if path == "" {
+			path = "(root)"
+		}
+		output += fmt.Sprintf("%s %s %s: %s\n", levelString, change.Kind, prettylogs.Underline(path), change.Message)
+	}
+	output += fmt.Sprintf("%d change(s), %d breaking\n", len(changes), breaking)
+	return output
+}
@@ -0,0 +1,216 @@
+package draft
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// keywords whose value is a schema
+var schemaKeywords = []string{
+	"additionalItems", "additionalProperties", "contains", "contentSchema", "else", "if", "items", "not",
+	"propertyNames", "then", "unevaluatedItems", "unevaluatedProperties",
+}
+
+// keywords whose value is an array of schemas
+var schemaArrayKeywords = []string{"allOf", "anyOf", "items", "oneOf", "prefixItems"}
+
+// keywords whose value is a map of schemas
+var schemaMapKeywords = []string{"$defs", "definitions", "dependencies", "dependentSchemas", "patternProperties", "properties"}
+
+// Convert a JSON Schema document to another draft. The source draft is detected from $schema, though most keywords
+// are rewritten based on their shape (an items array is always a tuple) so documents without one convert as well:
+//   - definitions <-> $defs (including $refs into them)
+//   - dependencies <-> dependentSchemas and dependentRequired
+//   - items array and additionalItems <-> prefixItems and items
+//   - boolean exclusiveMinimum/exclusiveMaximum (draft-4) -> numeric
+func Convert(data []byte, to Draft) ([]byte, error) {
+	if !slices.Contains(Targets, to) {
+		return nil, fmt.Errorf("converting to %s is not supported", to)
+	}
+
+	root, err := decodeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	c := converter{from: detect(root), to: to}
+	c.convert(root)
+
+	root.Set("$schema", to.MetaSchema())
+	_ = root.MoveToFront("$schema")
+
+	return encodeDocument(root)
+}
+
+type converter struct {
+	from Draft
+	to   Draft
+}
+
+func (c *converter) convert(node any) {
+	// boolean schemas have nothing to convert
+	obj, isObject := node.(*object)
+	if !isObject {
+		return
+	}
+
+	for _, keyword := range schemaKeywords {
+		if value, exists := obj.Get(keyword); exists {
+			c.convert(value)
+		}
+	}
+	for _, keyword := range schemaArrayKeywords {
+		if values, isArray := get(obj, keyword).([]any); isArray {
+			for _, value := range values {
+				c.convert(value)
+			}
+		}
+	}
+	for _, keyword := range schemaMapKeywords {
+		if values, isMap := get(obj, keyword).(*object); isMap {
+			for entry := values.Oldest(); entry != nil; entry = entry.Next() {
+				c.convert(entry.Value)
+			}
+		}
+	}
+
+	if c.from == Draft4 {
+		if id, isString := get(obj, "id").(string); isString {
+			replaceKey(obj, "id", pair("$id", id))
+		}
+	}
+
+	c.convertDefinitions(obj)
+	c.convertDependencies(obj)
+	c.convertItems(obj)
+	convertExclusiveLimit(obj, "exclusiveMinimum", "minimum")
+	convertExclusiveLimit(obj, "exclusiveMaximum", "maximum")
+}
+
+func (c *converter) convertDefinitions(obj *object) {
+	from, to := "definitions", "$defs"
+	if !c.to.modern() {
+		from, to = to, from
+	}
+
+	if definitions, exists := obj.Get(from); exists {
+		if _, conflict := obj.Get(to); !conflict {
+			replaceKey(obj, from, pair(to, definitions))
+		}
+	}
+
+	// only references into this document, other files keep their own keywords
+	if ref, isString := get(obj, "$ref").(string); isString && strings.HasPrefix(ref, "#/"+from+"/") {
+		obj.Set("$ref", "#/"+to+"/"+strings.TrimPrefix(ref, "#/"+from+"/"))
+	}
+}
+
+// dependencies holds both what became dependentSchemas (schema values) and dependentRequired (array values)
+func (c *converter) convertDependencies(obj *object) {
+	if c.to.modern() {
+		dependencies, isMap := get(obj, "dependencies").(*object)
+		if !isMap {
+			return
+		}
+
+		dependentSchemas := orderedmap.New[string, any]()
+		dependentRequired := orderedmap.New[string, any]()
+		for dep := dependencies.Oldest(); dep != nil; dep = dep.Next() {
+			if _, isArray := dep.Value.([]any); isArray {
+				dependentRequired.Set(dep.Key, dep.Value)
+			} else {
+				dependentSchemas.Set(dep.Key, dep.Value)
+			}
+		}
+
+		replacements := []orderedmap.Pair[string, any]{}
+		if dependentSchemas.Len() > 0 {
+			replacements = append(replacements, pair("dependentSchemas", dependentSchemas))
+		}
+		if dependentRequired.Len() > 0 {
+			replacements = append(replacements, pair("dependentRequired", dependentRequired))
+		}
+		replaceKey(obj, "dependencies", replacements...)
+		return
+	}
+
+	dependentSchemas, hasSchemas := get(obj, "dependentSchemas").(*object)
+	dependentRequired, hasRequired := get(obj, "dependentRequired").(*object)
+	if !hasSchemas && !hasRequired {
+		return
+	}
+
+	dependencies := orderedmap.New[string, any]()
+	for _, deps := range []*object{dependentSchemas, dependentRequired} {
+		if deps == nil {
+			continue
+		}
+		for dep := deps.Oldest(); dep != nil; dep = dep.Next() {
+			dependencies.Set(dep.Key, dep.Value)
+		}
+	}
+
+	// the merged keyword takes the place of whichever came first
+	for key := obj.Oldest(); key != nil; key = key.Next() {
+		if key.Key == "dependentSchemas" || key.Key == "dependentRequired" {
+			replaceKey(obj, key.Key, pair("dependencies", dependencies))
+			break
+		}
+	}
+	obj.Delete("dependentSchemas")
+	obj.Delete("dependentRequired")
+}
+
+// Tuples are an items array (with additionalItems for the rest) before 2020-12, and prefixItems (with items for the
+// rest) after
+func (c *converter) convertItems(obj *object) {
+	if c.to == Draft202012 {
+		tuple, isArray := get(obj, "items").([]any)
+		if !isArray {
+			return
+		}
+		if additionalItems, exists := obj.Get("additionalItems"); exists {
+			replaceKey(obj, "items", pair("prefixItems", tuple), pair("items", additionalItems))
+			obj.Delete("additionalItems")
+		} else {
+			replaceKey(obj, "items", pair("prefixItems", tuple))
+		}
+		return
+	}
+
+	tuple, isArray := get(obj, "prefixItems").([]any)
+	if !isArray {
+		return
+	}
+	if items, exists := obj.Get("items"); exists {
+		replaceKey(obj, "items", pair("additionalItems", items))
+	}
+	replaceKey(obj, "prefixItems", pair("items", tuple))
+}
+
+// In draft-4 exclusiveMinimum is a boolean that modifies minimum, since draft-6 it's the limit itself
+func convertExclusiveLimit(obj *object, exclusiveKeyword, limitKeyword string) {
+	exclusive, isBool := get(obj, exclusiveKeyword).(bool)
+	if !isBool {
+		return
+	}
+	limit, hasLimit := obj.Get(limitKeyword)
+	if !exclusive || !hasLimit {
+		obj.Delete(exclusiveKeyword)
+		return
+	}
+	obj.Set(exclusiveKeyword, limit)
+	obj.Delete(limitKeyword)
+}
+
+func get(obj *object, key string) any {
+	value, _ := obj.Get(key)
+	return value
+}
+
+func pair(key string, value any) orderedmap.Pair[string, any] {
+	return orderedmap.Pair[string, any]{Key: key, Value: value}
+}
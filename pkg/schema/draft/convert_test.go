@@ -0,0 +1,91 @@
+package draft_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/schema/draft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	type testData struct {
+		name string
+		to   draft.Draft
+	}
+	tests := []testData{
+		{
+			name: "draft7",
+			to:   draft.Draft202012,
+		},
+		{
+			name: "draft7",
+			to:   draft.Draft201909,
+		},
+		{
+			name: "draft202012",
+			to:   draft.Draft7,
+		},
+		{
+			name: "draft4",
+			to:   draft.Draft7,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name+" to "+string(tc.to), func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tc.name+".json"))
+			require.NoError(t, err)
+
+			want, err := os.ReadFile(filepath.Join("testdata", tc.name+"."+string(tc.to)+".json"))
+			require.NoError(t, err)
+
+			got, err := draft.Convert(data, tc.to)
+			require.NoError(t, err)
+
+			require.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+func TestConvertUnsupportedDraft(t *testing.T) {
+	_, err := draft.Convert([]byte(`{}`), draft.Draft4)
+	require.EqualError(t, err, "converting to draft-4 is not supported")
+}
+
+func TestDetect(t *testing.T) {
+	type testData struct {
+		name     string
+		document string
+		want     draft.Draft
+	}
+	tests := []testData{
+		{
+			name:     "draft-7",
+			document: `{"$schema": "http://json-schema.org/draft-07/schema#"}`,
+			want:     draft.Draft7,
+		},
+		{
+			name:     "without fragment",
+			document: `{"$schema": "http://json-schema.org/draft-04/schema"}`,
+			want:     draft.Draft4,
+		},
+		{
+			name:     "2020-12",
+			document: `{"$schema": "https://json-schema.org/draft/2020-12/schema"}`,
+			want:     draft.Draft202012,
+		},
+		{
+			name:     "missing",
+			document: `{"type": "object"}`,
+			want:     "",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := draft.Detect([]byte(tc.document))
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
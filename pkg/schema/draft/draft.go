@@ -0,0 +1,78 @@
+package draft
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Draft is a version of the JSON Schema specification
+type Draft string
+
+const (
+	Draft4      Draft = "draft-4"
+	Draft6      Draft = "draft-6"
+	Draft7      Draft = "draft-7"
+	Draft201909 Draft = "2019-09"
+	Draft202012 Draft = "2020-12"
+)
+
+var metaSchemas = map[Draft]string{
+	Draft4:      "http://json-schema.org/draft-04/schema#",
+	Draft6:      "http://json-schema.org/draft-06/schema#",
+	Draft7:      "http://json-schema.org/draft-07/schema#",
+	Draft201909: "https://json-schema.org/draft/2019-09/schema",
+	Draft202012: "https://json-schema.org/draft/2020-12/schema",
+}
+
+// Drafts a document can be converted to
+var Targets = []Draft{Draft7, Draft201909, Draft202012}
+
+// Parse a draft name (draft-7, 2020-12)
+func Parse(name string) (Draft, error) {
+	d := Draft(name)
+	if _, exists := metaSchemas[d]; !exists {
+		return "", fmt.Errorf("unknown JSON Schema draft %q", name)
+	}
+	return d, nil
+}
+
+// MetaSchema returns the $schema URI of the draft
+func (d Draft) MetaSchema() string {
+	return metaSchemas[d]
+}
+
+// modern drafts (2019-09 and later) replaced definitions and dependencies with $defs, dependentSchemas and dependentRequired
+func (d Draft) modern() bool {
+	return d == Draft201909 || d == Draft202012
+}
+
+// Detect the draft of a document from its $schema. The draft is empty if $schema is missing or isn't a known meta-schema
+func Detect(data []byte) (Draft, error) {
+	root, err := decodeDocument(data)
+	if err != nil {
+		return "", err
+	}
+	return detect(root), nil
+}
+
+func detect(root *object) Draft {
+	metaSchema, _ := root.Get("$schema")
+	uri, isString := metaSchema.(string)
+	if !isString {
+		return ""
+	}
+
+	for d, known := range metaSchemas {
+		if normalizeURI(uri) == normalizeURI(known) {
+			return d
+		}
+	}
+	return ""
+}
+
+// meta-schema URIs are commonly written with or without the scheme being https or the trailing empty fragment
+func normalizeURI(uri string) string {
+	uri = strings.TrimPrefix(uri, "http://")
+	uri = strings.TrimPrefix(uri, "https://")
+	return strings.TrimSuffix(uri, "#")
+}
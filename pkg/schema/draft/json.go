@@ -0,0 +1,115 @@
+package draft
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// Documents are converted as plain JSON rather than schema.Schema, since the keywords of older drafts don't fit
+// its fields (items can be an array, exclusiveMinimum can be a boolean). Objects keep the order of their keys so
+// the converted document only differs where keywords were rewritten
+type object = orderedmap.OrderedMap[string, any]
+
+func decodeDocument(data []byte) (*object, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value, err := decodeValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	root, isObject := value.(*object)
+	if !isObject {
+		return nil, errors.New("a JSON Schema document must be an object")
+	}
+	return root, nil
+}
+
+func decodeValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		obj := orderedmap.New[string, any]()
+		for decoder.More() {
+			keyToken, keyErr := decoder.Token()
+			if keyErr != nil {
+				return nil, keyErr
+			}
+			key, isString := keyToken.(string)
+			if !isString {
+				return nil, fmt.Errorf("unexpected object key %v", keyToken)
+			}
+			value, valueErr := decodeValue(decoder)
+			if valueErr != nil {
+				return nil, valueErr
+			}
+			obj.Set(key, value)
+		}
+		// closing brace
+		_, err = decoder.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for decoder.More() {
+			value, valueErr := decodeValue(decoder)
+			if valueErr != nil {
+				return nil, valueErr
+			}
+			arr = append(arr, value)
+		}
+		// closing bracket
+		_, err = decoder.Token()
+		return arr, err
+	default:
+		return token, nil
+	}
+}
+
+func encodeDocument(root *object) ([]byte, error) {
+	compact, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	var indented bytes.Buffer
+	if indentErr := json.Indent(&indented, compact, "", "  "); indentErr != nil {
+		return nil, indentErr
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes(), nil
+}
+
+// Replace a key with one or more keys at the same position
+func replaceKey(obj *object, key string, replacements ...orderedmap.Pair[string, any]) {
+	pairs := []orderedmap.Pair[string, any]{}
+	for pair := obj.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Key == key {
+			pairs = append(pairs, replacements...)
+			continue
+		}
+		if !containsKey(replacements, pair.Key) {
+			pairs = append(pairs, *pair)
+		}
+	}
+
+	for pair := obj.Oldest(); pair != nil; pair = obj.Oldest() {
+		obj.Delete(pair.Key)
+	}
+	obj.AddPairs(pairs...)
+}
+
+func containsKey(pairs []orderedmap.Pair[string, any], key string) bool {
+	for _, pair := range pairs {
+		if pair.Key == key {
+			return true
+		}
+	}
+	return false
+}
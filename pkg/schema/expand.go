@@ -1,67 +1,176 @@
 package schema
 
 import (
+	"maps"
 	"slices"
+	"strconv"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
-// Walk an object schema and flatten all the potential properties from oneOf/anyOf/allOf, dependencies
-func ExpandProperties(schema *Schema) *orderedmap.OrderedMap[string, *Schema] {
-	result := schema.Properties
+// Conflict is a keyword that is defined differently by schemas that all apply (the schema and its allOf branches
+// for instance), so they can't be merged into one schema. The first definition is kept
+type Conflict struct {
+	Path    string
+	Message string
+}
 
-	if result == nil {
-		result = orderedmap.New[string, *Schema]()
-	}
+// Flatten a schema into one without allOf/anyOf/oneOf, if/then/else or dependencies, so it can be converted to a
+// language without them:
+//   - allOf branches are intersected with the schema: their constraints are combined and their required properties
+//     added. Conflicting constraints are reported
+//   - oneOf/anyOf branches are unioned: their types and enums are combined and a property is only required if it's
+//     required in every branch
+//   - then/else and dependent schemas only apply sometimes, so they only add optional properties. A dependent schema
+//     on a required property always applies and is intersected instead
+//
+// Properties, items and additional properties are flattened as well. Paths are dotted property names
+func Flatten(s *Schema) (*Schema, []Conflict) {
+	f := flattener{conflicts: []Conflict{}}
+	return f.flatten("", s).Schema, f.conflicts
+}
 
-	for _, item := range schema.OneOf {
-		oneOf := ExpandProperties(item)
-		result = mergeProperties(result, oneOf)
+// Flatten an object schema and return all its potential properties
+func ExpandProperties(schema *Schema) *orderedmap.OrderedMap[string, *Schema] {
+	flattened, _ := Flatten(schema)
+	if flattened.Properties == nil {
+		return orderedmap.New[string, *Schema]()
 	}
-	for _, item := range schema.AnyOf {
-		anyOf := ExpandProperties(item)
-		result = mergeProperties(result, anyOf)
+	return flattened.Properties
+}
+
+type flattener struct {
+	conflicts []Conflict
+}
+
+// A flattened schema, along with the properties that only some branches of a union define. Those properties are
+// unconstrained when the other branches apply, so an intersection keeps the full definition of a property over them
+type flatSchema struct {
+	*Schema
+	partial map[string]bool
+}
+
+func (f *flattener) flatten(path string, s *Schema) flatSchema {
+	if s == nil || s.Boolean != nil {
+		return flatSchema{Schema: s}
 	}
-	for _, item := range schema.AllOf {
-		allOf := ExpandProperties(item)
-		result = mergeProperties(result, allOf)
+
+	base := *s
+	base.AllOf, base.AnyOf, base.OneOf = nil, nil, nil
+	base.If, base.Then, base.Else = nil, nil, nil
+	base.DependentSchemas = nil
+	base.Required = slices.Clone(s.Required)
+	if _, isMap := base.Dependencies.(map[string]*Schema); isMap {
+		base.Dependencies = nil
 	}
+	f.flattenChildren(path, &base)
 
-	// if dependencies is a map, we need to expand it
-	if dependentSchema, ok := schema.Dependencies.(map[string]*Schema); ok {
-		for _, item := range dependentSchema {
-			dep := ExpandProperties(item)
-			result = mergeProperties(result, dep)
+	result := flatSchema{Schema: &base}
+	for _, branch := range s.AllOf {
+		result = f.intersect(path, result, f.flatten(path, branch))
+	}
+	for _, branches := range [][]*Schema{s.OneOf, s.AnyOf} {
+		if len(branches) == 0 {
+			continue
 		}
+		union := f.flatten(path, branches[0])
+		for _, branch := range branches[1:] {
+			union = f.union(path, union, f.flatten(path, branch))
+		}
+		result = f.intersect(path, result, union)
+	}
+	if s.If != nil && (s.Then != nil || s.Else != nil) {
+		result = f.intersect(path, result, f.union(path, f.flattenOrEmpty(path, s.Then), f.flattenOrEmpty(path, s.Else)))
 	}
 
-	if schema.If != nil {
-		if schema.Then != nil {
-			result = mergeProperties(result, ExpandProperties(schema.Then))
+	return f.flattenDependencies(path, s, result)
+}
+
+func (f *flattener) flattenChildren(path string, s *Schema) {
+	if s.Properties != nil {
+		properties := orderedmap.New[string, *Schema]()
+		for prop := s.Properties.Oldest(); prop != nil; prop = prop.Next() {
+			properties.Set(prop.Key, f.flatten(propertyPath(path, prop.Key), prop.Value).Schema)
 		}
-		if schema.Else != nil {
-			result = mergeProperties(result, ExpandProperties(schema.Else))
+		s.Properties = properties
+	}
+	if s.Items != nil {
+		s.Items = f.flatten(path+"[*]", s.Items).Schema
+	}
+	if s.PrefixItems != nil {
+		prefixItems := make([]*Schema, len(s.PrefixItems))
+		for i, item := range s.PrefixItems {
+			prefixItems[i] = f.flatten(path+"["+strconv.Itoa(i)+"]", item).Schema
 		}
+		s.PrefixItems = prefixItems
+	}
+	if addProps, isSchema := s.AdditionalProperties.(*Schema); isSchema {
+		s.AdditionalProperties = f.flatten(propertyPath(path, "*"), addProps).Schema
 	}
+}
 
-	return result
+// a missing then or else allows anything
+func (f *flattener) flattenOrEmpty(path string, s *Schema) flatSchema {
+	if s == nil {
+		return flatSchema{Schema: new(Schema)}
+	}
+	return f.flatten(path, s)
 }
 
-// Merge two set of properties together. If the same properties exist in both base and combine, base is preserved and combine is ignored
-func mergeProperties(base, combine *orderedmap.OrderedMap[string, *Schema]) *orderedmap.OrderedMap[string, *Schema] {
-	merged := orderedmap.New[string, *Schema]()
-	existing := []string{}
+// Dependencies apply when a property is present, so always if the property is required
+func (f *flattener) flattenDependencies(path string, s *Schema, result flatSchema) flatSchema {
+	if result.Boolean != nil {
+		return result
+	}
 
-	for pair := base.Oldest(); pair != nil; pair = pair.Next() {
-		existing = append(existing, pair.Key)
-		merged.AddPairs(*pair)
+	dependentSchemas := map[string]*Schema{}
+	if dependencies, isMap := s.Dependencies.(map[string]*Schema); isMap {
+		for trigger, dependency := range dependencies {
+			dependentSchemas[trigger] = dependency
+		}
+	}
+	for trigger, dependency := range s.DependentSchemas {
+		dependentSchemas[trigger] = dependency
+	}
+	for _, trigger := range sortedKeys(dependentSchemas) {
+		dependency := f.flatten(path, dependentSchemas[trigger])
+		if !slices.Contains(result.Required, trigger) {
+			dependency = f.union(path, dependency, flatSchema{Schema: new(Schema)})
+		}
+		result = f.intersect(path, result, dependency)
 	}
 
-	for pair := combine.Oldest(); pair != nil; pair = pair.Next() {
-		if !slices.Contains(existing, pair.Key) {
-			merged.AddPairs(*pair)
+	dependentRequired := map[string][]string{}
+	if dependencies, isMap := s.Dependencies.(map[string][]string); isMap {
+		for trigger, names := range dependencies {
+			dependentRequired[trigger] = names
+		}
+	}
+	for trigger, names := range s.DependentRequired {
+		dependentRequired[trigger] = append(dependentRequired[trigger], names...)
+	}
+	// a required property can make others required, so keep going until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for _, trigger := range slices.Sorted(maps.Keys(dependentRequired)) {
+			names := dependentRequired[trigger]
+			if !slices.Contains(result.Required, trigger) {
+				continue
+			}
+			for _, name := range names {
+				if !slices.Contains(result.Required, name) {
+					result.Required = append(result.Required, name)
+					changed = true
+				}
+			}
 		}
 	}
+	return result
+}
 
-	return merged
+func propertyPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
 }
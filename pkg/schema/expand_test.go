@@ -1,10 +1,12 @@
 package schema_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
 	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/stretchr/testify/require"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
@@ -160,3 +162,69 @@ func TestExpandProperties(t *testing.T) {
 		})
 	}
 }
+
+func TestFlatten(t *testing.T) {
+	type testData struct {
+		name      string
+		schema    string
+		want      string
+		conflicts []schema.Conflict
+	}
+	tests := []testData{
+		{
+			name:   "allOf intersects",
+			schema: `{"required": ["name"], "properties": {"name": {"type": "string", "maxLength": 20}, "size": {"type": "number", "minimum": 1}}, "allOf": [{"required": ["size"], "properties": {"name": {"maxLength": 10, "minLength": 2}, "size": {"type": "integer", "minimum": 0, "enum": [1, 2, 3]}}}]}`,
+			want:   `{"properties": {"name": {"type": "string", "maxLength": 10, "minLength": 2}, "size": {"type": "integer", "enum": [1, 2, 3], "minimum": 1}}, "required": ["name", "size"]}`,
+		},
+		{
+			name:   "oneOf unions",
+			schema: `{"oneOf": [{"required": ["kind", "size"], "properties": {"kind": {"const": "db"}, "size": {"type": "integer"}}}, {"required": ["kind"], "properties": {"kind": {"const": "cache"}, "ttl": {"type": "string"}}}]}`,
+			want:   `{"properties": {"kind": {"enum": ["db", "cache"]}, "size": {"type": "integer"}, "ttl": {"type": "string"}}, "required": ["kind"]}`,
+		},
+		{
+			name:   "anyOf types",
+			schema: `{"properties": {"port": {"anyOf": [{"type": "integer", "maximum": 100}, {"type": "string", "maximum": 200}]}}}`,
+			want:   `{"properties": {"port": {"type": ["integer", "string"], "maximum": 200}}}`,
+		},
+		{
+			name:   "then adds optional properties",
+			schema: `{"required": ["kind"], "properties": {"kind": {"type": "string"}}, "if": {"properties": {"kind": {"const": "db"}}}, "then": {"required": ["size"], "properties": {"kind": {"const": "db"}, "size": {"type": "integer"}}}}`,
+			want:   `{"properties": {"kind": {"type": "string"}, "size": {"type": "integer"}}, "required": ["kind"]}`,
+		},
+		{
+			name:   "dependencies",
+			schema: `{"required": ["a"], "properties": {"a": {"type": "string"}, "b": {"type": "string"}}, "dependentRequired": {"a": ["b"]}, "dependentSchemas": {"a": {"properties": {"c": {"type": "string"}}, "required": ["c"]}, "b": {"properties": {"d": {"type": "string"}}, "required": ["d"]}}}`,
+			want:   `{"properties": {"a": {"type": "string"}, "b": {"type": "string"}, "c": {"type": "string"}, "d": {"type": "string"}}, "required": ["a", "c", "b"], "dependentRequired": {"a": ["b"]}}`,
+		},
+		{
+			name:   "legacy array dependencies",
+			schema: `{"required": ["a"], "properties": {"a": {"type": "string"}, "b": {"type": "string"}}, "dependencies": {"a": ["b"]}}`,
+			want:   `{"properties": {"a": {"type": "string"}, "b": {"type": "string"}}, "required": ["a", "b"], "dependencies": {"a": ["b"]}}`,
+		},
+		{
+			name:   "conflicts",
+			schema: `{"properties": {"db": {"type": "object", "properties": {"port": {"type": "integer", "minimum": 10}}}}, "allOf": [{"properties": {"db": {"properties": {"port": {"type": "string", "maximum": 5}}}}}, {"properties": {"db": {"properties": {"port": {"default": 5}}}}}]}`,
+			want:   `{"properties": {"db": {"type": "object", "properties": {"port": {"type": "integer", "default": 5, "maximum": 5, "minimum": 10}}}}}`,
+			conflicts: []schema.Conflict{
+				{Path: "db.port", Message: "type integer conflicts with string"},
+				{Path: "db.port", Message: "minimum 10 is greater than maximum 5"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var sch schema.Schema
+			require.NoError(t, json.Unmarshal([]byte(tc.schema), &sch))
+
+			got, conflicts := schema.Flatten(&sch)
+			gotBytes, err := json.Marshal(got)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.want, string(gotBytes))
+
+			if tc.conflicts == nil {
+				tc.conflicts = []schema.Conflict{}
+			}
+			require.Equal(t, tc.conflicts, conflicts)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Load reads a schema from a file and resolves its references. Recursive references are left in the schema
+func Load(path string) (*Schema, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sch := new(Schema)
+	if unmarshalErr := json.Unmarshal(bytes, sch); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, unmarshalErr)
+	}
+
+	if _, resolveErr := Resolve(sch, path); resolveErr != nil {
+		return nil, resolveErr
+	}
+	return sch, nil
+}
@@ -1,8 +1,48 @@
 package schema
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// the keys of the keywords Schema has fields for, anything else is kept in Extras
+var knownKeys = schemaKeys()
+
+func schemaKeys() map[string]bool {
+	keys := map[string]bool{}
+	schemaType := reflect.TypeOf(Schema{})
+	for i := 0; i < schemaType.NumField(); i++ {
+		name, _, _ := strings.Cut(schemaType.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// marshals a copy, so the raw fields can be filled in without modifying the schema
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if s.Boolean != nil {
+		return json.Marshal(*s.Boolean)
+	}
+
+	s.TypeRaw = nil
+	if s.Types != nil || s.Type != "" {
+		var typeValue any = s.Type
+		if s.Types != nil {
+			typeValue = s.Types
+		}
+		typeBytes, err := json.Marshal(typeValue)
+		if err != nil {
+			return nil, err
+		}
+		typeRaw := json.RawMessage(typeBytes)
+		s.TypeRaw = &typeRaw
+	}
 
-func (s *Schema) MarshalJSON() ([]byte, error) {
 	if s.AdditionalProperties != nil {
 		addPropBytes, err := json.Marshal(s.AdditionalProperties)
 		if err != nil {
@@ -22,15 +62,62 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 	}
 
 	type Alias Schema
-	return json.Marshal(&struct {
+	schemaBytes, err := json.Marshal(&struct {
 		*Alias
 	}{
-		Alias: (*Alias)(s),
+		Alias: (*Alias)(&s),
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appendExtras(schemaBytes, s.Extras)
 }
 
-// need a custom unmarshaler to deal with the ambiguity of additionalProperties
+// Add unrecognized keywords to the end of the marshaled schema, sorted by key so the output is stable
+func appendExtras(schemaBytes []byte, extras map[string]any) ([]byte, error) {
+	keys := []string{}
+	for key := range extras {
+		// a field always takes precedence over an extra with the same key
+		if !knownKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return schemaBytes, nil
+	}
+	slices.Sort(keys)
+
+	buf := bytes.NewBuffer(bytes.TrimSuffix(schemaBytes, []byte("}")))
+	for i, key := range keys {
+		if i > 0 || len(schemaBytes) > 2 {
+			buf.WriteByte(',')
+		}
+		keyBytes, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := json.Marshal(extras[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyBytes)
+		buf.WriteByte(':')
+		buf.Write(valueBytes)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// need a custom unmarshaler to deal with the ambiguity of additionalProperties, type and boolean schemas, and to
+// keep unrecognized keywords in Extras
 func (s *Schema) UnmarshalJSON(data []byte) error {
+	var boolSchema bool
+	if err := json.Unmarshal(data, &boolSchema); err == nil {
+		*s = Schema{Boolean: &boolSchema}
+		return nil
+	}
+
 	// we need to redirect to another type to avoid infinite recursion loop
 	type Alias Schema
 	alias := &struct {
@@ -43,6 +130,23 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	if s.TypeRaw != nil {
+		var typeString string
+		err := json.Unmarshal(*s.TypeRaw, &typeString)
+		if err != nil {
+			// type is an array of types
+			var typeList []string
+			err = json.Unmarshal(*s.TypeRaw, &typeList)
+			if err != nil {
+				return err
+			}
+			s.Types = typeList
+		} else {
+			s.Type = typeString
+		}
+		s.TypeRaw = nil
+	}
+
 	if s.AdditionalPropertiesRaw != nil {
 		var addPropBool bool
 		err := json.Unmarshal(*s.AdditionalPropertiesRaw, &addPropBool)
@@ -78,5 +182,26 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+	for key, value := range keywords {
+		if knownKeys[key] {
+			continue
+		}
+		// numbers are kept as json.Number, so they're written back exactly as they were
+		decoder := json.NewDecoder(bytes.NewReader(value))
+		decoder.UseNumber()
+		var extra any
+		if err := decoder.Decode(&extra); err != nil {
+			return err
+		}
+		if s.Extras == nil {
+			s.Extras = map[string]any{}
+		}
+		s.Extras[key] = extra
+	}
+
 	return nil
 }
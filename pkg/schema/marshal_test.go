@@ -64,6 +64,62 @@ func TestMarshal(t *testing.T) {
 				)),
 			},
 		},
+		{
+			name: "types",
+			schema: schema.Schema{
+				Properties: orderedmap.New[string, *schema.Schema](orderedmap.WithInitialData[string, *schema.Schema](
+					orderedmap.Pair[string, *schema.Schema]{
+						Key: "nullableString",
+						Value: &schema.Schema{
+							Types: []string{"string", "null"},
+						},
+					},
+					orderedmap.Pair[string, *schema.Schema]{
+						Key: "tuple",
+						Value: &schema.Schema{
+							Type: "array",
+							PrefixItems: []*schema.Schema{
+								{
+									Type: "string",
+								},
+							},
+							Items: schema.Boolean(false),
+						},
+					},
+					orderedmap.Pair[string, *schema.Schema]{
+						Key:   "anything",
+						Value: schema.Boolean(true),
+					},
+					orderedmap.Pair[string, *schema.Schema]{
+						Key: "nothing",
+						Value: &schema.Schema{
+							Not: schema.Boolean(true),
+						},
+					},
+				)),
+			},
+		},
+		{
+			name: "extras",
+			schema: schema.Schema{
+				Properties: orderedmap.New[string, *schema.Schema](orderedmap.WithInitialData[string, *schema.Schema](
+					orderedmap.Pair[string, *schema.Schema]{
+						Key: "name",
+						Value: &schema.Schema{
+							Type: "string",
+							Extras: map[string]any{
+								"$md.immutable": true,
+								"x-order":       float64(1),
+							},
+						},
+					},
+				)),
+				Extras: map[string]any{
+					"$id":         "https://example.com/schemas/database.json",
+					"x-generator": "airlock",
+				},
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -134,6 +190,62 @@ func TestUnmarshal(t *testing.T) {
 				)),
 			},
 		},
+		{
+			name: "types",
+			want: schema.Schema{
+				Properties: orderedmap.New[string, *schema.Schema](orderedmap.WithInitialData[string, *schema.Schema](
+					orderedmap.Pair[string, *schema.Schema]{
+						Key: "nullableString",
+						Value: &schema.Schema{
+							Types: []string{"string", "null"},
+						},
+					},
+					orderedmap.Pair[string, *schema.Schema]{
+						Key: "tuple",
+						Value: &schema.Schema{
+							Type: "array",
+							PrefixItems: []*schema.Schema{
+								{
+									Type: "string",
+								},
+							},
+							Items: schema.Boolean(false),
+						},
+					},
+					orderedmap.Pair[string, *schema.Schema]{
+						Key:   "anything",
+						Value: schema.Boolean(true),
+					},
+					orderedmap.Pair[string, *schema.Schema]{
+						Key: "nothing",
+						Value: &schema.Schema{
+							Not: schema.Boolean(true),
+						},
+					},
+				)),
+			},
+		},
+		{
+			name: "extras",
+			want: schema.Schema{
+				Properties: orderedmap.New[string, *schema.Schema](orderedmap.WithInitialData[string, *schema.Schema](
+					orderedmap.Pair[string, *schema.Schema]{
+						Key: "name",
+						Value: &schema.Schema{
+							Type: "string",
+							Extras: map[string]any{
+								"$md.immutable": true,
+								"x-order":       json.Number("1"),
+							},
+						},
+					},
+				)),
+				Extras: map[string]any{
+					"$id":         "https://example.com/schemas/database.json",
+					"x-generator": "airlock",
+				},
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -154,3 +266,54 @@ func TestUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalExtrasOrder(t *testing.T) {
+	type testData struct {
+		name   string
+		schema *schema.Schema
+		want   string
+	}
+	tests := []testData{
+		{
+			name: "sorted after keywords",
+			schema: &schema.Schema{
+				Type: "string",
+				Extras: map[string]any{
+					"x-b":  1,
+					"$md":  true,
+					"x-a":  "a",
+					"type": "ignored",
+				},
+			},
+			want: `{"type":"string","$md":true,"x-a":"a","x-b":1}`,
+		},
+		{
+			name: "only extras",
+			schema: &schema.Schema{
+				Extras: map[string]any{
+					"x-b": 1,
+					"x-a": "a",
+				},
+			},
+			want: `{"x-a":"a","x-b":1}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(tc.schema)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestUnmarshalExtrasPrecision(t *testing.T) {
+	input := `{"x-id":12345678901234567890,"x-ratio":0.10000000000000000001}`
+
+	got := schema.Schema{}
+	require.NoError(t, json.Unmarshal([]byte(input), &got))
+
+	bytes, err := json.Marshal(&got)
+	require.NoError(t, err)
+	require.Equal(t, input, string(bytes))
+}
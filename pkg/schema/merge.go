@@ -0,0 +1,388 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// Intersect two flattened schemas that both apply, so a value has to be valid against both
+func (f *flattener) intersect(path string, a, b flatSchema) flatSchema {
+	switch {
+	case a.Boolean != nil && *a.Boolean, b.Boolean != nil && !*b.Boolean:
+		return b
+	case b.Boolean != nil, a.Boolean != nil:
+		return a
+	}
+
+	merged := *a.Schema
+	fillZero(&merged, b.Schema)
+
+	f.intersectTypes(path, &merged, a.Schema, b.Schema)
+	f.intersectValues(path, &merged, a.Schema, b.Schema)
+	f.intersectLimits(path, &merged, a.Schema, b.Schema)
+
+	merged.Required = slices.Clone(a.Required)
+	for _, name := range b.Required {
+		if !slices.Contains(merged.Required, name) {
+			merged.Required = append(merged.Required, name)
+		}
+	}
+
+	result := flatSchema{Schema: &merged, partial: a.partial}
+	switch {
+	case a.Properties != nil && b.Properties != nil:
+		merged.Properties, result.partial = f.mergeProperties(path, a, b, f.intersectProperty)
+	case a.Properties == nil:
+		result.partial = b.partial
+	}
+
+	if a.Items != nil && b.Items != nil {
+		merged.Items = f.intersect(path+"[*]", flatSchema{Schema: a.Items}, flatSchema{Schema: b.Items}).Schema
+	}
+
+	aAddProps, aIsSchema := a.AdditionalProperties.(*Schema)
+	bAddProps, bIsSchema := b.AdditionalProperties.(*Schema)
+	switch {
+	case a.AdditionalProperties == false || b.AdditionalProperties == false:
+		merged.AdditionalProperties = false
+	case aIsSchema && bIsSchema:
+		merged.AdditionalProperties = f.intersect(propertyPath(path, "*"), flatSchema{Schema: aAddProps}, flatSchema{Schema: bAddProps}).Schema
+	}
+
+	return result
+}
+
+// A property only some branches of a union define is unconstrained when the others apply, so the full definition
+// of the property wins
+func (f *flattener) intersectProperty(path string, a, b flatSchema, aPartial, bPartial bool) (flatSchema, bool) {
+	switch {
+	case bPartial && !aPartial:
+		return a, false
+	case aPartial && !bPartial:
+		return b, false
+	case aPartial && bPartial:
+		return f.union(path, a, b), true
+	}
+	return f.intersect(path, a, b), false
+}
+
+// Union two flattened schemas where either applies, so a value has to be valid against at least one
+func (f *flattener) union(path string, a, b flatSchema) flatSchema {
+	switch {
+	case a.Boolean != nil && *a.Boolean, b.Boolean != nil && !*b.Boolean:
+		return a
+	case b.Boolean != nil, a.Boolean != nil:
+		return b
+	}
+
+	merged := Schema{}
+	// anything not handled below is kept if both schemas agree on it
+	mergedValue := reflect.ValueOf(&merged).Elem()
+	aValue, bValue := reflect.ValueOf(a.Schema).Elem(), reflect.ValueOf(b.Schema).Elem()
+	for i := 0; i < mergedValue.NumField(); i++ {
+		if reflect.DeepEqual(aValue.Field(i).Interface(), bValue.Field(i).Interface()) {
+			mergedValue.Field(i).Set(aValue.Field(i))
+		}
+	}
+	merged.Title = firstNonEmpty(a.Title, b.Title)
+	merged.Description = firstNonEmpty(a.Description, b.Description)
+	merged.Comment = firstNonEmpty(a.Comment, b.Comment)
+
+	unionTypes(&merged, a.Schema, b.Schema)
+	unionValues(&merged, a.Schema, b.Schema)
+	mergeLimits(&merged, a.Schema, b.Schema, false)
+
+	// a property is only required if every branch requires it
+	merged.Required = nil
+	for _, name := range a.Required {
+		if slices.Contains(b.Required, name) {
+			merged.Required = append(merged.Required, name)
+		}
+	}
+
+	result := flatSchema{Schema: &merged}
+	if a.Properties != nil || b.Properties != nil {
+		merged.Properties, result.partial = f.mergeProperties(path, a, b, func(propPath string, aProp, bProp flatSchema, aPartial, bPartial bool) (flatSchema, bool) {
+			return f.union(propPath, aProp, bProp), aPartial || bPartial
+		})
+		// and a property only one of them defines is unconstrained when the other applies
+		for prop := merged.Properties.Oldest(); prop != nil; prop = prop.Next() {
+			_, inA := getProperty(a.Schema, prop.Key)
+			_, inB := getProperty(b.Schema, prop.Key)
+			if !inA || !inB {
+				result.partial[prop.Key] = true
+			}
+		}
+	}
+
+	if a.Items != nil && b.Items != nil {
+		merged.Items = f.union(path+"[*]", flatSchema{Schema: a.Items}, flatSchema{Schema: b.Items}).Schema
+	}
+
+	return result
+}
+
+type combineFunc func(path string, a, b flatSchema, aPartial, bPartial bool) (flatSchema, bool)
+
+// Merge the properties of two schemas in order, combining the ones both define. A property only one of them defines
+// is kept as is
+func (f *flattener) mergeProperties(path string, a, b flatSchema, combine combineFunc) (*orderedmap.OrderedMap[string, *Schema], map[string]bool) {
+	properties := orderedmap.New[string, *Schema]()
+	partial := map[string]bool{}
+	for _, side := range []flatSchema{a, b} {
+		if side.Properties == nil {
+			continue
+		}
+		for prop := side.Properties.Oldest(); prop != nil; prop = prop.Next() {
+			if _, exists := properties.Get(prop.Key); exists {
+				continue
+			}
+			aProp, inA := getProperty(a.Schema, prop.Key)
+			bProp, inB := getProperty(b.Schema, prop.Key)
+			if !inA || !inB {
+				properties.Set(prop.Key, prop.Value)
+				partial[prop.Key] = side.partial[prop.Key]
+				continue
+			}
+			combined, isPartial := combine(propertyPath(path, prop.Key), flatSchema{Schema: aProp}, flatSchema{Schema: bProp}, a.partial[prop.Key], b.partial[prop.Key])
+			properties.Set(prop.Key, combined.Schema)
+			partial[prop.Key] = isPartial
+		}
+	}
+	return properties, partial
+}
+
+func (f *flattener) intersectTypes(path string, merged, a, b *Schema) {
+	aTypes, bTypes := a.TypeNames(), b.TypeNames()
+	if len(aTypes) == 0 || len(bTypes) == 0 {
+		return
+	}
+	types := []string{}
+	for _, t := range aTypes {
+		switch {
+		case slices.Contains(bTypes, t):
+			types = append(types, t)
+		case t == "integer" && slices.Contains(bTypes, "number"), t == "number" && slices.Contains(bTypes, "integer"):
+			types = append(types, "integer")
+		}
+	}
+	if len(types) == 0 {
+		f.conflict(path, "type %s conflicts with %s", strings.Join(aTypes, "|"), strings.Join(bTypes, "|"))
+		return
+	}
+	merged.SetTypeNames(types)
+}
+
+// no type allows any type
+func unionTypes(merged, a, b *Schema) {
+	aTypes, bTypes := a.TypeNames(), b.TypeNames()
+	if len(aTypes) == 0 || len(bTypes) == 0 {
+		merged.SetTypeNames(nil)
+		return
+	}
+	types := slices.Clone(aTypes)
+	for _, t := range bTypes {
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	if slices.Contains(types, "number") {
+		types = slices.DeleteFunc(types, func(t string) bool { return t == "integer" })
+	}
+	merged.SetTypeNames(types)
+}
+
+func (f *flattener) intersectValues(path string, merged, a, b *Schema) {
+	if len(a.Enum) > 0 && len(b.Enum) > 0 {
+		merged.Enum = []any{}
+		for _, value := range a.Enum {
+			if containsValue(b.Enum, value) {
+				merged.Enum = append(merged.Enum, value)
+			}
+		}
+		if len(merged.Enum) == 0 {
+			f.conflict(path, "enum %s conflicts with %s", formatValue(a.Enum), formatValue(b.Enum))
+			merged.Enum = a.Enum
+		}
+	}
+	if merged.Const != nil && len(merged.Enum) > 0 && !containsValue(merged.Enum, merged.Const) {
+		f.conflict(path, "const %s isn't in enum %s", formatValue(merged.Const), formatValue(merged.Enum))
+	}
+
+	keywords := []struct {
+		name string
+		a, b any
+	}{
+		{"const", a.Const, b.Const},
+		{"pattern", optional(a.Pattern), optional(b.Pattern)},
+		{"format", optional(a.Format), optional(b.Format)},
+		{"multipleOf", optional(a.MultipleOf), optional(b.MultipleOf)},
+		{"default", a.Default, b.Default},
+	}
+	for _, keyword := range keywords {
+		if keyword.a != nil && keyword.b != nil && !reflect.DeepEqual(keyword.a, keyword.b) {
+			f.conflict(path, "%s %s conflicts with %s", keyword.name, formatValue(keyword.a), formatValue(keyword.b))
+		}
+	}
+}
+
+// A const is an enum with one value, and a schema with neither allows any value
+func unionValues(merged, a, b *Schema) {
+	aValues, bValues := allowedValues(a), allowedValues(b)
+	if aValues == nil || bValues == nil {
+		merged.Const, merged.Enum = nil, nil
+		return
+	}
+	if a.Const != nil && reflect.DeepEqual(a.Const, b.Const) {
+		return
+	}
+	values := slices.Clone(aValues)
+	for _, value := range bValues {
+		if !containsValue(values, value) {
+			values = append(values, value)
+		}
+	}
+	merged.Const, merged.Enum = nil, values
+}
+
+func allowedValues(s *Schema) []any {
+	if len(s.Enum) > 0 {
+		return s.Enum
+	}
+	if s.Const != nil {
+		return []any{s.Const}
+	}
+	return nil
+}
+
+func containsValue(values []any, value any) bool {
+	return slices.ContainsFunc(values, func(other any) bool { return reflect.DeepEqual(value, other) })
+}
+
+func (f *flattener) intersectLimits(path string, merged, a, b *Schema) {
+	mergeLimits(merged, a, b, true)
+
+	minimum, minErr := merged.Minimum.Float64()
+	maximum, maxErr := merged.Maximum.Float64()
+	if minErr == nil && maxErr == nil && minimum > maximum {
+		f.conflict(path, "minimum %s is greater than maximum %s", merged.Minimum, merged.Maximum)
+	}
+	lengths := []struct {
+		keyword string
+		lower   *uint64
+		upper   *uint64
+	}{
+		{"Length", merged.MinLength, merged.MaxLength},
+		{"Items", merged.MinItems, merged.MaxItems},
+		{"Properties", merged.MinProperties, merged.MaxProperties},
+	}
+	for _, length := range lengths {
+		if length.lower != nil && length.upper != nil && *length.lower > *length.upper {
+			f.conflict(path, "min%s %d is greater than max%s %d", length.keyword, *length.lower, length.keyword, *length.upper)
+		}
+	}
+}
+
+// An intersection keeps the stricter of two limits, and a union the looser (dropping limits only one schema has)
+func mergeLimits(merged, a, b *Schema, stricter bool) {
+	merged.Minimum = mergeNumberLimit(a.Minimum, b.Minimum, true, stricter)
+	merged.ExclusiveMinimum = mergeNumberLimit(a.ExclusiveMinimum, b.ExclusiveMinimum, true, stricter)
+	merged.Maximum = mergeNumberLimit(a.Maximum, b.Maximum, false, stricter)
+	merged.ExclusiveMaximum = mergeNumberLimit(a.ExclusiveMaximum, b.ExclusiveMaximum, false, stricter)
+	merged.MinLength = mergeLengthLimit(a.MinLength, b.MinLength, true, stricter)
+	merged.MaxLength = mergeLengthLimit(a.MaxLength, b.MaxLength, false, stricter)
+	merged.MinItems = mergeLengthLimit(a.MinItems, b.MinItems, true, stricter)
+	merged.MaxItems = mergeLengthLimit(a.MaxItems, b.MaxItems, false, stricter)
+	merged.MinContains = mergeLengthLimit(a.MinContains, b.MinContains, true, stricter)
+	merged.MaxContains = mergeLengthLimit(a.MaxContains, b.MaxContains, false, stricter)
+	merged.MinProperties = mergeLengthLimit(a.MinProperties, b.MinProperties, true, stricter)
+	merged.MaxProperties = mergeLengthLimit(a.MaxProperties, b.MaxProperties, false, stricter)
+}
+
+// lower bounds are stricter when higher, upper bounds when lower
+func mergeNumberLimit(a, b json.Number, lower, stricter bool) json.Number {
+	aLimit, aErr := a.Float64()
+	bLimit, bErr := b.Float64()
+	switch {
+	case aErr != nil && bErr != nil, !stricter && (aErr != nil || bErr != nil):
+		return ""
+	case aErr != nil:
+		return b
+	case bErr != nil:
+		return a
+	case (aLimit > bLimit) == (lower == stricter):
+		return a
+	default:
+		return b
+	}
+}
+
+func mergeLengthLimit(a, b *uint64, lower, stricter bool) *uint64 {
+	switch {
+	case a == nil && b == nil, !stricter && (a == nil || b == nil):
+		return nil
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case (*a > *b) == (lower == stricter):
+		return a
+	default:
+		return b
+	}
+}
+
+// a conflict is only reported once, even if more branches are merged into the conflicting schema
+func (f *flattener) conflict(path, format string, args ...any) {
+	conflict := Conflict{Path: path, Message: fmt.Sprintf(format, args...)}
+	if !slices.Contains(f.conflicts, conflict) {
+		f.conflicts = append(f.conflicts, conflict)
+	}
+}
+
+// Copy every keyword that is set in src and not in dst
+func fillZero(dst, src *Schema) {
+	dstValue := reflect.ValueOf(dst).Elem()
+	srcValue := reflect.ValueOf(src).Elem()
+	for i := 0; i < srcValue.NumField(); i++ {
+		if dstValue.Field(i).IsZero() {
+			dstValue.Field(i).Set(srcValue.Field(i))
+		}
+	}
+}
+
+// nil if the keyword isn't set, so it can be compared like const and default
+func optional[T comparable](value T) any {
+	var zero T
+	if value == zero {
+		return nil
+	}
+	return value
+}
+
+func firstNonEmpty(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
+
+func getProperty(s *Schema, name string) (*Schema, bool) {
+	if s.Properties == nil {
+		return nil, false
+	}
+	return s.Properties.Get(name)
+}
+
+func formatValue(value any) string {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(bytes)
+}
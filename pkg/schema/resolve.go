@@ -0,0 +1,330 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Resolve replaces every $ref in the schema with the schema it references. References can point into the
+// same document (#/$defs/foo, #/definitions/foo, #/properties/bar) or into other files relative to the
+// document (common.json#/$defs/foo). Keywords alongside a $ref take precedence over the referenced schema.
+// Recursive references can't be expanded, so they are left as they are (the first time round the cycle) and
+// returned for the caller to warn about. documentPath is the file the schema was loaded from, relative file
+// references are resolved against the working directory if it's empty
+func Resolve(sch *Schema, documentPath string) ([]RecursiveRef, error) {
+	if documentPath != "" {
+		absPath, err := filepath.Abs(documentPath)
+		if err != nil {
+			return nil, err
+		}
+		documentPath = absPath
+	}
+
+	r := resolver{
+		documents: map[string]*Schema{documentPath: sch},
+	}
+	if err := r.resolve(sch, documentPath); err != nil {
+		return nil, err
+	}
+	return recursiveRefs(sch), nil
+}
+
+// RecursiveRef is a $ref that was left in the schema because it references one of the schemas containing it
+type RecursiveRef struct {
+	// JSON pointer to the schema with the $ref
+	Pointer string
+	Ref     string
+}
+
+// The $refs left after resolving are the recursive ones. The definitions themselves aren't reported, since they
+// only matter where they're used
+func recursiveRefs(sch *Schema) []RecursiveRef {
+	refs := []RecursiveRef{}
+	_, _ = Walk(sch, Visitor{Pre: func(path string, node *Schema) (*Schema, error) {
+		parent := path[:max(strings.LastIndex(path, "/"), 0)]
+		if strings.HasSuffix(parent, "/$defs") || strings.HasSuffix(parent, "/definitions") {
+			return node, ErrSkipChildren
+		}
+		if node.Ref != "" {
+			refs = append(refs, RecursiveRef{Pointer: path, Ref: node.Ref})
+		}
+		return node, nil
+	}})
+	return refs
+}
+
+// SourcePath returns the path of the file being read, or an empty string if the reader isn't a file
+func SourcePath(in io.Reader) string {
+	if file, ok := in.(*os.File); ok && file != os.Stdin {
+		return file.Name()
+	}
+	return ""
+}
+
+type resolver struct {
+	// documents loaded so far, keyed by absolute path
+	documents map[string]*Schema
+	// targets currently being expanded, used to detect cycles. Targets are identified by absolute path and
+	// pointer, regardless of how the reference was written
+	stack []string
+}
+
+func (r *resolver) resolve(sch *Schema, documentPath string) error {
+	if sch == nil {
+		return nil
+	}
+
+	for _, child := range subschemas(sch) {
+		if err := r.resolve(child, documentPath); err != nil {
+			return err
+		}
+	}
+
+	if sch.Ref == "" {
+		return nil
+	}
+
+	targetPath, pointer, err := splitRef(sch.Ref, documentPath)
+	if err != nil {
+		return err
+	}
+
+	current := targetPath + "#" + pointer
+	if slices.Contains(r.stack, current) {
+		// expanding it again would never end
+		return nil
+	}
+
+	document, err := r.load(targetPath)
+	if err != nil {
+		return err
+	}
+	target, err := lookupPointer(document, pointer)
+	if err != nil {
+		return fmt.Errorf("unable to resolve $ref %q: %w", sch.Ref, err)
+	}
+
+	resolved, err := Clone(target)
+	if err != nil {
+		return err
+	}
+
+	r.stack = append(r.stack, current)
+	err = r.resolve(resolved, targetPath)
+	r.stack = r.stack[:len(r.stack)-1]
+	if err != nil {
+		return err
+	}
+
+	sch.Ref = ""
+	overlay(resolved, sch)
+	*sch = *resolved
+	return nil
+}
+
+func (r *resolver) load(documentPath string) (*Schema, error) {
+	if document, exists := r.documents[documentPath]; exists {
+		return document, nil
+	}
+
+	bytes, err := os.ReadFile(documentPath)
+	if err != nil {
+		return nil, err
+	}
+	document := new(Schema)
+	if unmarshalErr := json.Unmarshal(bytes, document); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", documentPath, unmarshalErr)
+	}
+	r.documents[documentPath] = document
+	return document, nil
+}
+
+// Split a reference into the absolute path of the document it points to and the JSON pointer within it
+func splitRef(ref, documentPath string) (string, string, error) {
+	location, fragment, _ := strings.Cut(ref, "#")
+	if strings.Contains(location, "://") {
+		return "", "", fmt.Errorf("remote $ref %q is not supported", ref)
+	}
+
+	pointer, err := url.PathUnescape(fragment)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid $ref %q: %w", ref, err)
+	}
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return "", "", fmt.Errorf("$ref %q must be a JSON pointer, anchors are not supported", ref)
+	}
+
+	if location == "" {
+		return documentPath, pointer, nil
+	}
+
+	targetPath := location
+	if !filepath.IsAbs(targetPath) {
+		targetPath = filepath.Join(filepath.Dir(documentPath), location)
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return "", "", err
+	}
+	return absPath, pointer, nil
+}
+
+// Walk a JSON pointer (RFC 6901) through the keywords of a schema
+func lookupPointer(root *Schema, pointer string) (*Schema, error) {
+	if pointer == "" {
+		return root, nil
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[i])
+	}
+
+	current := root
+	for i := 0; i < len(tokens); i++ {
+		if current == nil {
+			break
+		}
+
+		keyword := tokens[i]
+		next := ""
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+
+		switch keyword {
+		case "$defs":
+			current = current.Definitions[next]
+			i++
+		case "definitions":
+			current = current.LegacyDefinitions[next]
+			i++
+		case "properties":
+			if current.Properties == nil {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			current, _ = current.Properties.Get(next)
+			i++
+		case "patternProperties":
+			current = current.PatternProperties[next]
+			i++
+		case "dependentSchemas":
+			current = current.DependentSchemas[next]
+			i++
+		case "dependencies":
+			dependentSchemas, _ := current.Dependencies.(map[string]*Schema)
+			current = dependentSchemas[next]
+			i++
+		case "allOf", "anyOf", "oneOf", "prefixItems":
+			index, err := strconv.Atoi(next)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q in %s", next, pointer)
+			}
+			list := map[string][]*Schema{
+				"allOf":       current.AllOf,
+				"anyOf":       current.AnyOf,
+				"oneOf":       current.OneOf,
+				"prefixItems": current.PrefixItems,
+			}[keyword]
+			if index < 0 || index >= len(list) {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			current = list[index]
+			i++
+		case "additionalProperties":
+			current, _ = current.AdditionalProperties.(*Schema)
+		case "items":
+			current = current.Items
+		case "contains":
+			current = current.Contains
+		case "not":
+			current = current.Not
+		case "if":
+			current = current.If
+		case "then":
+			current = current.Then
+		case "else":
+			current = current.Else
+		case "propertyNames":
+			current = current.PropertyNames
+		case "contentSchema":
+			current = current.ContentSchema
+		default:
+			return nil, fmt.Errorf("unsupported keyword %q in %s", keyword, pointer)
+		}
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("%s not found", pointer)
+	}
+	return current, nil
+}
+
+// All the schemas directly nested in a schema, except definitions which are only resolved when referenced
+func subschemas(sch *Schema) []*Schema {
+	children := slices.Concat(sch.AllOf, sch.AnyOf, sch.OneOf, sch.PrefixItems)
+	children = append(children, sch.Not, sch.If, sch.Then, sch.Else, sch.Items, sch.Contains, sch.PropertyNames, sch.ContentSchema)
+
+	if sch.Properties != nil {
+		for prop := sch.Properties.Oldest(); prop != nil; prop = prop.Next() {
+			children = append(children, prop.Value)
+		}
+	}
+	for _, name := range sortedKeys(sch.PatternProperties) {
+		children = append(children, sch.PatternProperties[name])
+	}
+	if addProps, isSchema := sch.AdditionalProperties.(*Schema); isSchema {
+		children = append(children, addProps)
+	}
+	for _, name := range sortedKeys(sch.DependentSchemas) {
+		children = append(children, sch.DependentSchemas[name])
+	}
+	if dependentSchemas, isMap := sch.Dependencies.(map[string]*Schema); isMap {
+		for _, name := range sortedKeys(dependentSchemas) {
+			children = append(children, dependentSchemas[name])
+		}
+	}
+
+	return children
+}
+
+func sortedKeys(m map[string]*Schema) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Copy every keyword that is set in src over dst
+func overlay(dst, src *Schema) {
+	dstValue := reflect.ValueOf(dst).Elem()
+	srcValue := reflect.ValueOf(src).Elem()
+	for i := 0; i < srcValue.NumField(); i++ {
+		if field := srcValue.Field(i); !field.IsZero() {
+			dstValue.Field(i).Set(field)
+		}
+	}
+}
+
+// Clone deep copies a schema, so the same definition can be referenced (and modified) in multiple places
+func Clone(sch *Schema) (*Schema, error) {
+	bytes, err := json.Marshal(sch)
+	if err != nil {
+		return nil, err
+	}
+	copied := new(Schema)
+	if unmarshalErr := json.Unmarshal(bytes, copied); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return copied, nil
+}
@@ -0,0 +1,72 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	type testData struct {
+		name     string
+		wantRefs []schema.RecursiveRef
+		wantErr  string
+	}
+	tests := []testData{
+		{
+			name: "defs",
+		},
+		{
+			name: "definitions",
+		},
+		{
+			name: "file",
+		},
+		{
+			name: "override",
+		},
+		{
+			name: "circular",
+			wantRefs: []schema.RecursiveRef{
+				{Pointer: "/properties/tree/properties/child", Ref: "#/$defs/node"},
+			},
+		},
+		{
+			name:    "missing",
+			wantErr: `unable to resolve $ref "#/$defs/foo": /$defs/foo not found`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schemaPath := filepath.Join("testdata", "resolve", tc.name+".json")
+			bytes, err := os.ReadFile(schemaPath)
+			require.NoError(t, err)
+
+			var sch schema.Schema
+			require.NoError(t, json.Unmarshal(bytes, &sch))
+
+			refs, err := schema.Resolve(&sch, schemaPath)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.wantRefs == nil {
+				tc.wantRefs = []schema.RecursiveRef{}
+			}
+			require.Equal(t, tc.wantRefs, refs)
+
+			want, err := os.ReadFile(filepath.Join("testdata", "resolve", tc.name+".resolved.json"))
+			require.NoError(t, err)
+
+			got, err := json.Marshal(&sch)
+			require.NoError(t, err)
+
+			require.JSONEq(t, string(want), string(got))
+		})
+	}
+}
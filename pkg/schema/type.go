@@ -0,0 +1,43 @@
+package schema
+
+import "slices"
+
+// Boolean returns a boolean schema, true allows any value and false allows none
+func Boolean(value bool) *Schema {
+	return &Schema{Boolean: &value}
+}
+
+// TypeNames returns the types the schema allows, whether type is a single type or an array of types
+func (s *Schema) TypeNames() []string {
+	if s.Types != nil {
+		return s.Types
+	}
+	if s.Type == "" {
+		return nil
+	}
+	return []string{s.Type}
+}
+
+// SetTypeNames sets type to a single type, or the array form if there's more than one
+func (s *Schema) SetTypeNames(types []string) {
+	s.Type, s.Types = "", nil
+	switch len(types) {
+	case 0:
+	case 1:
+		s.Type = types[0]
+	default:
+		s.Types = types
+	}
+}
+
+// NullableType returns the type of the schema ignoring "null", and whether null is allowed. ["string", "null"]
+// is a nullable string. The type is empty if the schema allows any type or more than one type besides null
+func (s *Schema) NullableType() (string, bool) {
+	types := s.TypeNames()
+	nullable := slices.Contains(types, "null")
+	types = slices.DeleteFunc(slices.Clone(types), func(t string) bool { return t == "null" })
+	if len(types) != 1 {
+		return "", nullable
+	}
+	return types[0], nullable
+}
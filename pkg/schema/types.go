@@ -12,22 +12,24 @@ type Schema struct {
 	// RFC draft-bhutton-json-schema-00
 	Version string `json:"$schema,omitempty"` // section 8.1.1
 	// ID          ID          `json:"$id,omitempty"`         // section 8.2.1
-	Anchor     string `json:"$anchor,omitempty"`     // section 8.2.2
-	Ref        string `json:"$ref,omitempty"`        // section 8.2.3.1
-	DynamicRef string `json:"$dynamicRef,omitempty"` // section 8.2.3.2
-	// Definitions Definitions `json:"$defs,omitempty"`       // section 8.2.4
-	Comment string `json:"$comment,omitempty"` // section 8.3
+	Anchor            string             `json:"$anchor,omitempty"`     // section 8.2.2
+	Ref               string             `json:"$ref,omitempty"`        // section 8.2.3.1
+	DynamicRef        string             `json:"$dynamicRef,omitempty"` // section 8.2.3.2
+	Definitions       map[string]*Schema `json:"$defs,omitempty"`       // section 8.2.4
+	LegacyDefinitions map[string]*Schema `json:"definitions,omitempty"` // draft-07 and earlier
+	Comment           string             `json:"$comment,omitempty"`    // section 8.3
 	// RFC draft-bhutton-json-schema-00 section 10.2.1 (Sub-schemas with logic)
 	AllOf []*Schema `json:"allOf,omitempty"` // section 10.2.1.1
 	AnyOf []*Schema `json:"anyOf,omitempty"` // section 10.2.1.2
 	OneOf []*Schema `json:"oneOf,omitempty"` // section 10.2.1.3
 	Not   *Schema   `json:"not,omitempty"`   // section 10.2.1.4
 	// RFC draft-bhutton-json-schema-00 section 10.2.2 (Apply sub-schemas conditionally)
-	If              *Schema          `json:"if,omitempty"`           // section 10.2.2.1
-	Then            *Schema          `json:"then,omitempty"`         // section 10.2.2.2
-	Else            *Schema          `json:"else,omitempty"`         // section 10.2.2.3
-	DependenciesRaw *json.RawMessage `json:"dependencies,omitempty"` // section 10.2.2.4
-	Dependencies    any              `json:"-"`                      // section 10.2.2.4
+	If               *Schema            `json:"if,omitempty"`               // section 10.2.2.1
+	Then             *Schema            `json:"then,omitempty"`             // section 10.2.2.2
+	Else             *Schema            `json:"else,omitempty"`             // section 10.2.2.3
+	DependentSchemas map[string]*Schema `json:"dependentSchemas,omitempty"` // section 10.2.2.4
+	DependenciesRaw  *json.RawMessage   `json:"dependencies,omitempty"`     // draft-07 and earlier
+	Dependencies     any                `json:"-"`                          // either a map[string]*Schema or a map[string][]string
 	// RFC draft-bhutton-json-schema-00 section 10.3.1 (arrays)
 	PrefixItems []*Schema `json:"prefixItems,omitempty"` // section 10.3.1.1
 	Items       *Schema   `json:"items,omitempty"`       // section 10.3.1.2  (replaces additionalItems)
@@ -41,7 +43,9 @@ type Schema struct {
 	// AdditionalProperties *Schema `json:"additionalProperties,omitempty"` // section 10.3.2.3
 	PropertyNames *Schema `json:"propertyNames,omitempty"` // section 10.3.2.4
 	// RFC draft-bhutton-json-schema-validation-00, section 6
-	Type              string              `json:"type,omitempty"`              // section 6.1.1
+	TypeRaw           *json.RawMessage    `json:"type,omitempty"`              // section 6.1.1
+	Type              string              `json:"-"`                           // section 6.1.1
+	Types             []string            `json:"-"`                           // section 6.1.1, the array form ["string", "null"]. Type is empty when it's set
 	Enum              []any               `json:"enum,omitempty"`              // section 6.1.2
 	Const             any                 `json:"const,omitempty"`             // section 6.1.3
 	MultipleOf        json.Number         `json:"multipleOf,omitempty"`        // section 6.2.1
@@ -76,5 +80,8 @@ type Schema struct {
 	WriteOnly   bool   `json:"writeOnly,omitempty"`   // section 9.4
 	Examples    []any  `json:"examples,omitempty"`    // section 9.5
 
+	// Boolean schemas (true allows any value, false allows none) are represented by setting only this field
+	Boolean *bool `json:"-"`
+
 	Extras map[string]any `json:"-"`
 }
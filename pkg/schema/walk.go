@@ -0,0 +1,191 @@
+package schema
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrSkipChildren can be returned by a Pre hook to walk past the children of a schema. The Post hook is still called
+var ErrSkipChildren = errors.New("skip children")
+
+// Visitor hooks are called for every schema in a tree, with the JSON pointer to the schema from the root ("" for
+// the root, "/properties/foo/items"). Pre is called before the children of a schema are walked and Post after.
+// Either hook can replace the schema by returning a different one, or remove it from its parent by returning nil.
+// Nil hooks are skipped
+type Visitor struct {
+	Pre  func(path string, node *Schema) (*Schema, error)
+	Post func(path string, node *Schema) (*Schema, error)
+}
+
+// Walk every sub-schema of a schema depth first, in the order the keywords are declared in Schema. Returns the
+// root, which is different from s if it was replaced by one of the hooks
+func Walk(s *Schema, visitor Visitor) (*Schema, error) {
+	w := walker{visitor: visitor}
+	return w.walk("", s)
+}
+
+type walker struct {
+	visitor Visitor
+}
+
+func (w *walker) walk(path string, node *Schema) (*Schema, error) {
+	if node == nil {
+		return node, nil
+	}
+
+	skipChildren := false
+	if w.visitor.Pre != nil {
+		var err error
+		node, err = w.visitor.Pre(path, node)
+		if errors.Is(err, ErrSkipChildren) {
+			skipChildren = true
+		} else if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			// removed by the hook
+			return node, nil
+		}
+	}
+
+	if !skipChildren {
+		if err := w.walkChildren(path, node); err != nil {
+			return nil, err
+		}
+	}
+
+	if w.visitor.Post != nil {
+		return w.visitor.Post(path, node)
+	}
+	return node, nil
+}
+
+func (w *walker) walkChildren(path string, node *Schema) error {
+	steps := []func() error{
+		func() error { return w.walkMap(path+"/$defs", node.Definitions) },
+		func() error { return w.walkMap(path+"/definitions", node.LegacyDefinitions) },
+		func() error { return w.walkSlice(path+"/allOf", &node.AllOf) },
+		func() error { return w.walkSlice(path+"/anyOf", &node.AnyOf) },
+		func() error { return w.walkSlice(path+"/oneOf", &node.OneOf) },
+		func() error { return w.walkField(path+"/not", &node.Not) },
+		func() error { return w.walkField(path+"/if", &node.If) },
+		func() error { return w.walkField(path+"/then", &node.Then) },
+		func() error { return w.walkField(path+"/else", &node.Else) },
+		func() error { return w.walkMap(path+"/dependentSchemas", node.DependentSchemas) },
+		func() error {
+			if dependentSchemas, isMap := node.Dependencies.(map[string]*Schema); isMap {
+				return w.walkMap(path+"/dependencies", dependentSchemas)
+			}
+			return nil
+		},
+		func() error { return w.walkSlice(path+"/prefixItems", &node.PrefixItems) },
+		func() error { return w.walkField(path+"/items", &node.Items) },
+		func() error { return w.walkField(path+"/contains", &node.Contains) },
+		func() error { return w.walkProperties(path+"/properties", node) },
+		func() error { return w.walkMap(path+"/patternProperties", node.PatternProperties) },
+		func() error {
+			addProps, isSchema := node.AdditionalProperties.(*Schema)
+			if !isSchema {
+				return nil
+			}
+			replaced, err := w.walk(path+"/additionalProperties", addProps)
+			if err != nil {
+				return err
+			}
+			if replaced == nil {
+				node.AdditionalProperties = nil
+			} else {
+				node.AdditionalProperties = replaced
+			}
+			return nil
+		},
+		func() error { return w.walkField(path+"/propertyNames", &node.PropertyNames) },
+		func() error { return w.walkField(path+"/contentSchema", &node.ContentSchema) },
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) walkField(path string, field **Schema) error {
+	replaced, err := w.walk(path, *field)
+	if err != nil {
+		return err
+	}
+	*field = replaced
+	return nil
+}
+
+func (w *walker) walkSlice(path string, field *[]*Schema) error {
+	if *field == nil {
+		return nil
+	}
+	kept := []*Schema{}
+	for i, child := range *field {
+		replaced, err := w.walk(path+"/"+strconv.Itoa(i), child)
+		if err != nil {
+			return err
+		}
+		if replaced != nil {
+			kept = append(kept, replaced)
+		}
+	}
+	*field = kept
+	return nil
+}
+
+// maps are walked in key order so the walk is deterministic
+func (w *walker) walkMap(path string, field map[string]*Schema) error {
+	for _, key := range sortedKeys(field) {
+		replaced, err := w.walk(path+"/"+escapePointer(key), field[key])
+		if err != nil {
+			return err
+		}
+		if replaced == nil {
+			delete(field, key)
+		} else {
+			field[key] = replaced
+		}
+	}
+	return nil
+}
+
+func (w *walker) walkProperties(path string, node *Schema) error {
+	if node.Properties == nil {
+		return nil
+	}
+	removed := []string{}
+	for prop := node.Properties.Oldest(); prop != nil; prop = prop.Next() {
+		replaced, err := w.walk(path+"/"+escapePointer(prop.Key), prop.Value)
+		if err != nil {
+			return err
+		}
+		if replaced == nil {
+			removed = append(removed, prop.Key)
+		} else {
+			prop.Value = replaced
+		}
+	}
+	for _, key := range removed {
+		node.Properties.Delete(key)
+	}
+	return nil
+}
+
+// Escape a key for use in a JSON pointer (RFC 6901)
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// AppendPointer appends keywords and keys to a JSON pointer, escaping them
+func AppendPointer(pointer string, tokens ...string) string {
+	for _, token := range tokens {
+		pointer += "/" + escapePointer(token)
+	}
+	return pointer
+}
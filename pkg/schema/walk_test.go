@@ -0,0 +1,170 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/massdriver-cloud/airlock/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+const walkSchema = `{
+	"$defs": {"name": {"type": "string"}},
+	"allOf": [{"required": ["a/b"]}],
+	"if": {"properties": {"kind": {"const": "db"}}},
+	"then": {"required": ["size"]},
+	"properties": {
+		"a/b": {"type": "array", "items": {"type": "integer"}},
+		"tuple": {"type": "array", "prefixItems": [{"type": "string"}], "items": false},
+		"tags": {"type": "object", "additionalProperties": {"type": "string"}}
+	},
+	"patternProperties": {"^x-": {"type": "string"}},
+	"dependencies": {"size": {"required": ["kind"]}}
+}`
+
+func TestWalk(t *testing.T) {
+	type testData struct {
+		name    string
+		visitor func(visited *[]string) schema.Visitor
+		want    []string
+	}
+	tests := []testData{
+		{
+			name: "pre order",
+			visitor: func(visited *[]string) schema.Visitor {
+				return schema.Visitor{
+					Pre: func(path string, node *schema.Schema) (*schema.Schema, error) {
+						*visited = append(*visited, path)
+						return node, nil
+					},
+				}
+			},
+			want: []string{
+				"",
+				"/$defs/name",
+				"/allOf/0",
+				"/if",
+				"/if/properties/kind",
+				"/then",
+				"/dependencies/size",
+				"/properties/a~1b",
+				"/properties/a~1b/items",
+				"/properties/tuple",
+				"/properties/tuple/prefixItems/0",
+				"/properties/tuple/items",
+				"/properties/tags",
+				"/properties/tags/additionalProperties",
+				"/patternProperties/^x-",
+			},
+		},
+		{
+			name: "post order",
+			visitor: func(visited *[]string) schema.Visitor {
+				return schema.Visitor{
+					Post: func(path string, node *schema.Schema) (*schema.Schema, error) {
+						*visited = append(*visited, path)
+						return node, nil
+					},
+				}
+			},
+			want: []string{
+				"/$defs/name",
+				"/allOf/0",
+				"/if/properties/kind",
+				"/if",
+				"/then",
+				"/dependencies/size",
+				"/properties/a~1b/items",
+				"/properties/a~1b",
+				"/properties/tuple/prefixItems/0",
+				"/properties/tuple/items",
+				"/properties/tuple",
+				"/properties/tags/additionalProperties",
+				"/properties/tags",
+				"/patternProperties/^x-",
+				"",
+			},
+		},
+		{
+			name: "skip children",
+			visitor: func(visited *[]string) schema.Visitor {
+				return schema.Visitor{
+					Pre: func(path string, node *schema.Schema) (*schema.Schema, error) {
+						if path != "" {
+							*visited = append(*visited, path)
+							return node, schema.ErrSkipChildren
+						}
+						return node, nil
+					},
+				}
+			},
+			want: []string{
+				"/$defs/name",
+				"/allOf/0",
+				"/if",
+				"/then",
+				"/dependencies/size",
+				"/properties/a~1b",
+				"/properties/tuple",
+				"/properties/tags",
+				"/patternProperties/^x-",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var sch schema.Schema
+			require.NoError(t, json.Unmarshal([]byte(walkSchema), &sch))
+
+			visited := []string{}
+			_, err := schema.Walk(&sch, tc.visitor(&visited))
+			require.NoError(t, err)
+			require.Equal(t, tc.want, visited)
+		})
+	}
+}
+
+func TestWalkReplace(t *testing.T) {
+	var sch schema.Schema
+	require.NoError(t, json.Unmarshal([]byte(walkSchema), &sch))
+
+	// widen integers to numbers and drop pattern properties
+	got, err := schema.Walk(&sch, schema.Visitor{
+		Post: func(path string, node *schema.Schema) (*schema.Schema, error) {
+			if path == "/patternProperties/^x-" {
+				return nil, nil
+			}
+			if node.Type == "integer" {
+				return &schema.Schema{Type: "number"}, nil
+			}
+			return node, nil
+		},
+	})
+	require.NoError(t, err)
+
+	aProp, _ := got.Properties.Get("a/b")
+	require.Equal(t, "number", aProp.Items.Type)
+	require.Empty(t, got.PatternProperties)
+}
+
+func TestWalkError(t *testing.T) {
+	var sch schema.Schema
+	require.NoError(t, json.Unmarshal([]byte(walkSchema), &sch))
+
+	failure := errors.New("failure")
+	_, err := schema.Walk(&sch, schema.Visitor{
+		Pre: func(path string, node *schema.Schema) (*schema.Schema, error) {
+			if path == "/properties/tags" {
+				return nil, failure
+			}
+			return node, nil
+		},
+	})
+	require.ErrorIs(t, err, failure)
+}
+
+func TestAppendPointer(t *testing.T) {
+	require.Equal(t, "", schema.AppendPointer(""))
+	require.Equal(t, "/properties/a~1b/properties/c~0d", schema.AppendPointer("/properties/a~1b", "properties", "c~d"))
+}
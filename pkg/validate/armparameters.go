@@ -0,0 +1,131 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+)
+
+// The $schema of ARM deployment parameter files ends with this, whatever the scope of the deployment is
+const armParametersSchema = "deploymentParameters.json"
+
+type armParameterFile struct {
+	Schema     string                    `json:"$schema"`
+	Parameters map[string]map[string]any `json:"parameters"`
+}
+
+func isARMParameters(content []byte) bool {
+	file := armParameterFile{}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.TrimSuffix(file.Schema, "#"), armParametersSchema)
+}
+
+// Parse an ARM deployment parameter file into an object of the parameter values, without the {"value": ...} each one
+// is wrapped in. Key Vault references are left out of the values, since they're only known when deploying, but they
+// still count as set
+func parseARMParameters(content []byte) (parsedDocument, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	file := armParameterFile{}
+	if err := decoder.Decode(&file); err != nil {
+		return parsedDocument{}, err
+	}
+
+	values := map[string]any{}
+	deferred := []string{}
+	for name, parameter := range file.Parameters {
+		if value, exists := parameter["value"]; exists {
+			values[name] = value
+		} else if _, exists := parameter["reference"]; exists {
+			deferred = append(deferred, name)
+		}
+	}
+
+	// errors are located at the values in the file, which are nested in parameters.<name>.value
+	positions := map[string]*result.Range{}
+	err := jsonPositions(content, func(path []string, rng *result.Range) {
+		switch {
+		case len(path) == 1 && path[0] == "parameters":
+			positions[rootContext] = rng
+		case len(path) >= 3 && path[0] == "parameters" && path[2] == "value":
+			positions[contextKey(append([]string{rootContext, path[1]}, path[3:]...))] = rng
+		}
+	})
+	if err != nil {
+		return parsedDocument{}, err
+	}
+	return parsedDocument{value: values, positions: positions, deferred: deferred}, nil
+}
+
+// Walk a JSON document, calling visit with the path (object keys and array indexes) and range of every value
+func jsonPositions(content []byte, visit func(path []string, rng *result.Range)) error {
+	scanner := jsonScanner{decoder: json.NewDecoder(bytes.NewReader(content)), content: content}
+	return scanner.walk(nil, visit)
+}
+
+type jsonScanner struct {
+	decoder *json.Decoder
+	content []byte
+}
+
+func (s *jsonScanner) walk(path []string, visit func(path []string, rng *result.Range)) error {
+	// the decoder only tells where tokens end, so the value starts after the whitespace and separators before it
+	start := int(s.decoder.InputOffset())
+	for start < len(s.content) && strings.IndexByte(" \t\r\n:,", s.content[start]) >= 0 {
+		start++
+	}
+
+	token, err := s.decoder.Token()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case json.Delim('{'):
+		for s.decoder.More() {
+			keyToken, keyErr := s.decoder.Token()
+			if keyErr != nil {
+				return keyErr
+			}
+			key, isString := keyToken.(string)
+			if !isString {
+				return fmt.Errorf("unexpected %v", keyToken)
+			}
+			if walkErr := s.walk(append(slices.Clip(path), key), visit); walkErr != nil {
+				return walkErr
+			}
+		}
+		if _, closeErr := s.decoder.Token(); closeErr != nil {
+			return closeErr
+		}
+	case json.Delim('['):
+		for index := 0; s.decoder.More(); index++ {
+			if walkErr := s.walk(append(slices.Clip(path), strconv.Itoa(index)), visit); walkErr != nil {
+				return walkErr
+			}
+		}
+		if _, closeErr := s.decoder.Token(); closeErr != nil {
+			return closeErr
+		}
+	}
+
+	visit(path, &result.Range{Start: s.position(start), End: s.position(int(s.decoder.InputOffset()))})
+	return nil
+}
+
+// The line and column of a byte offset. Columns count characters, not bytes
+func (s *jsonScanner) position(offset int) result.Position {
+	before := s.content[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return result.Position{
+		Line:   bytes.Count(before, []byte("\n")) + 1,
+		Column: utf8.RuneCount(before[lineStart:]) + 1,
+	}
+}
@@ -0,0 +1,332 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+)
+
+// Parse a .bicepparam file into an object of the parameters it sets. Only literal values can be validated, so
+// expressions (function calls, variables, string interpolation) are an error, and so is any statement other than
+// using and param
+func parseBicepParam(content []byte) (parsedDocument, error) {
+	parser := &bicepParamParser{content: string(content), line: 1, column: 1}
+	values := map[string]any{}
+	document := parsedDocument{value: values, positions: map[string]*result.Range{}}
+
+	start := parser.position()
+	for parser.skipSpace(); !parser.done(); parser.skipSpace() {
+		keywordStart := parser.position()
+		switch keyword := parser.identifier(); keyword {
+		case "using":
+			// the template (or none) the parameters are for, which doesn't matter when validating against a schema
+			parser.skipSpace()
+			if parser.peek() == '\'' {
+				if _, err := parser.string(); err != nil {
+					return parsedDocument{}, err
+				}
+			} else {
+				parser.identifier()
+			}
+		case "param":
+			parser.skipSpace()
+			name := parser.identifier()
+			if name == "" {
+				return parsedDocument{}, parser.errorf(parser.position(), "expected a parameter name")
+			}
+			parser.skipSpace()
+			if !parser.consume('=') {
+				return parsedDocument{}, parser.errorf(parser.position(), "expected = after param %s", name)
+			}
+			value, err := parser.value([]string{rootContext, name}, document.positions)
+			if err != nil {
+				return parsedDocument{}, err
+			}
+			values[name] = value
+		case "":
+			return parsedDocument{}, parser.errorf(keywordStart, "unexpected %q", parser.peek())
+		default:
+			return parsedDocument{}, parser.errorf(keywordStart, "only using and param statements are supported, not %s", keyword)
+		}
+	}
+	document.positions[rootContext] = &result.Range{Start: start, End: parser.position()}
+	return document, nil
+}
+
+type bicepParamParser struct {
+	content string
+	offset  int
+	line    int
+	column  int
+}
+
+func (p *bicepParamParser) done() bool {
+	return p.offset >= len(p.content)
+}
+
+func (p *bicepParamParser) rest() string {
+	return p.content[p.offset:]
+}
+
+// The next character, or 0 at the end of the file
+func (p *bicepParamParser) peek() rune {
+	if p.done() {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(p.rest())
+	return r
+}
+
+func (p *bicepParamParser) advance() rune {
+	r, size := utf8.DecodeRuneInString(p.rest())
+	p.offset += size
+	if r == '\n' {
+		p.line++
+		p.column = 1
+	} else {
+		p.column++
+	}
+	return r
+}
+
+func (p *bicepParamParser) consume(r rune) bool {
+	if p.done() || p.peek() != r {
+		return false
+	}
+	p.advance()
+	return true
+}
+
+func (p *bicepParamParser) position() result.Position {
+	return result.Position{Line: p.line, Column: p.column}
+}
+
+func (p *bicepParamParser) errorf(position result.Position, format string, args ...any) error {
+	return fmt.Errorf("line %d, column %d: %s", position.Line, position.Column, fmt.Sprintf(format, args...))
+}
+
+// Skip whitespace, newlines and comments
+func (p *bicepParamParser) skipSpace() {
+	for !p.done() {
+		switch {
+		case unicode.IsSpace(p.peek()):
+			p.advance()
+		case strings.HasPrefix(p.rest(), "//"):
+			for !p.done() && p.peek() != '\n' {
+				p.advance()
+			}
+		case strings.HasPrefix(p.rest(), "/*"):
+			end := strings.Index(p.rest()[2:], "*/")
+			if end < 0 {
+				end = len(p.rest())
+			} else {
+				end += 4
+			}
+			for stop := p.offset + end; p.offset < stop; {
+				p.advance()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *bicepParamParser) identifier() string {
+	start := p.offset
+	for !p.done() {
+		r := p.peek()
+		if !unicode.IsLetter(r) && r != '_' && (p.offset == start || !unicode.IsDigit(r)) {
+			break
+		}
+		p.advance()
+	}
+	return p.content[start:p.offset]
+}
+
+func (p *bicepParamParser) value(context []string, positions map[string]*result.Range) (any, error) {
+	p.skipSpace()
+	start := p.position()
+
+	var value any
+	var err error
+	switch r := p.peek(); {
+	case strings.HasPrefix(p.rest(), "'''"):
+		value, err = p.multilineString()
+	case r == '\'':
+		value, err = p.string()
+	case r == '[':
+		value, err = p.array(context, positions)
+	case r == '{':
+		value, err = p.object(context, positions)
+	case r == '-' || unicode.IsDigit(r):
+		value, err = p.number()
+	case unicode.IsLetter(r) || r == '_':
+		switch word := p.identifier(); word {
+		case "true", "false":
+			value = word == "true"
+		case "null":
+			value = nil
+		default:
+			err = p.errorf(start, "only literal values can be validated, %s is an expression", word)
+		}
+	case p.done():
+		err = p.errorf(start, "unexpected end of file")
+	default:
+		err = p.errorf(start, "unexpected %q", r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	positions[contextKey(context)] = &result.Range{Start: start, End: p.position()}
+	return value, nil
+}
+
+func (p *bicepParamParser) array(context []string, positions map[string]*result.Range) ([]any, error) {
+	p.advance()
+	items := []any{}
+	for {
+		p.skipSpace()
+		if p.consume(']') {
+			return items, nil
+		}
+		item, err := p.value(append(slices.Clip(context), strconv.Itoa(len(items))), positions)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		p.skipSpace()
+		p.consume(',')
+	}
+}
+
+func (p *bicepParamParser) object(context []string, positions map[string]*result.Range) (map[string]any, error) {
+	p.advance()
+	mapping := map[string]any{}
+	for {
+		p.skipSpace()
+		if p.consume('}') {
+			return mapping, nil
+		}
+
+		keyStart := p.position()
+		var key string
+		if p.peek() == '\'' {
+			var err error
+			if key, err = p.string(); err != nil {
+				return nil, err
+			}
+		} else if key = p.identifier(); key == "" {
+			return nil, p.errorf(keyStart, "expected a property name")
+		}
+		p.skipSpace()
+		if !p.consume(':') {
+			return nil, p.errorf(p.position(), "expected : after property %s", key)
+		}
+
+		value, err := p.value(append(slices.Clip(context), key), positions)
+		if err != nil {
+			return nil, err
+		}
+		mapping[key] = value
+		p.skipSpace()
+		p.consume(',')
+	}
+}
+
+func (p *bicepParamParser) number() (json.Number, error) {
+	start := p.position()
+	startOffset := p.offset
+	p.consume('-')
+	for !p.done() && unicode.IsDigit(p.peek()) {
+		p.advance()
+	}
+	number := p.content[startOffset:p.offset]
+	if number == "-" {
+		return "", p.errorf(start, "expected a number after -")
+	}
+	return json.Number(number), nil
+}
+
+func (p *bicepParamParser) string() (string, error) {
+	start := p.position()
+	p.advance()
+	var builder strings.Builder
+	for {
+		if p.done() || p.peek() == '\n' {
+			return "", p.errorf(start, "unterminated string")
+		}
+		switch r := p.advance(); r {
+		case '\'':
+			return builder.String(), nil
+		case '\\':
+			escaped, err := p.escape()
+			if err != nil {
+				return "", err
+			}
+			builder.WriteRune(escaped)
+		case '$':
+			if p.peek() == '{' {
+				return "", p.errorf(start, "only literal values can be validated, string interpolation is an expression")
+			}
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+}
+
+var bicepEscapes = map[rune]rune{'n': '\n', 'r': '\r', 't': '\t', '\\': '\\', '\'': '\'', '$': '$'}
+
+func (p *bicepParamParser) escape() (rune, error) {
+	start := p.position()
+	if p.done() {
+		return 0, p.errorf(start, "unterminated string")
+	}
+	r := p.advance()
+	if escaped, exists := bicepEscapes[r]; exists {
+		return escaped, nil
+	}
+	if r != 'u' || !p.consume('{') {
+		return 0, p.errorf(start, "invalid escape sequence \\%c", r)
+	}
+	end := strings.IndexRune(p.rest(), '}')
+	if end < 0 {
+		return 0, p.errorf(start, "unterminated unicode escape sequence")
+	}
+	code, err := strconv.ParseUint(p.rest()[:end], 16, 32)
+	if err != nil {
+		return 0, p.errorf(start, "invalid unicode escape sequence \\u{%s}", p.rest()[:end])
+	}
+	for range end + 1 {
+		p.advance()
+	}
+	return rune(code), nil
+}
+
+// Multi-line strings are taken as they are, without escapes or interpolation. Like Bicep, a newline straight after
+// the opening quotes isn't part of the string
+func (p *bicepParamParser) multilineString() (string, error) {
+	start := p.position()
+	for range 3 {
+		p.advance()
+	}
+	end := strings.Index(p.rest(), "'''")
+	if end < 0 {
+		return "", p.errorf(start, "unterminated multi-line string")
+	}
+	value := p.rest()[:end]
+	for stop := p.offset + end + 3; p.offset < stop; {
+		p.advance()
+	}
+	if trimmed, found := strings.CutPrefix(value, "\r\n"); found {
+		return trimmed, nil
+	}
+	return strings.TrimPrefix(value, "\n"), nil
+}
@@ -0,0 +1,75 @@
+package validate
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+)
+
+// gojsonschema describes where an error is with a context, starting from this
+const rootContext = "(root)"
+
+// The formats documents can be in. Anything that isn't JSON is parsed into the value JSON would have before it's
+// validated
+type format int
+
+const (
+	formatJSON format = iota
+	formatYAML
+	formatTFVars
+	formatBicepParam
+	formatARMParameters
+)
+
+// A document parsed from a format other than JSON
+type parsedDocument struct {
+	value any
+	// the range of every value in the document, keyed by contextKey
+	positions map[string]*result.Range
+	// top level values that are set, but only known when deploying
+	deferred []string
+}
+
+// The format is detected by the extension, then by the content
+func documentFormat(path string, content []byte) format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tfvars":
+		return formatTFVars
+	case ".bicepparam":
+		return formatBicepParam
+	}
+	if isYAML(path, content) {
+		return formatYAML
+	}
+	if isARMParameters(content) {
+		return formatARMParameters
+	}
+	return formatJSON
+}
+
+// Every format but YAML has a single document in a file
+func parseDocuments(docFormat format, path string, content []byte) ([]parsedDocument, error) {
+	var document parsedDocument
+	var err error
+	switch docFormat {
+	case formatYAML:
+		return parseYAML(content)
+	case formatTFVars:
+		document, err = parseTFVars(path, content)
+	case formatBicepParam:
+		document, err = parseBicepParam(content)
+	case formatARMParameters:
+		document, err = parseARMParameters(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []parsedDocument{document}, nil
+}
+
+// The key for a location in a document, in the same form as the context of a gojsonschema error. The tokens are
+// joined with a character that can't be in a key, since keys can have dots in them
+func contextKey(context []string) string {
+	return strings.Join(context, "\x00")
+}
@@ -1,9 +1,15 @@
 package validate
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 
+	"github.com/massdriver-cloud/airlock/pkg/schema/draft"
 	"github.com/xeipuuv/gojsonschema"
 )
 
@@ -11,18 +17,142 @@ const filePrefix = "file://"
 
 var loaderPrefixPattern = regexp.MustCompile(`^(file|http|https)://`)
 
-// Load a JSON Schema with or without a path prefix
+// Load a JSON Schema with or without a path prefix. Local files can be JSON or YAML
 func Loader(path string) gojsonschema.JSONLoader {
-	var ref string
+	return &fileLoader{
+		JSONLoader: gojsonschema.NewReferenceLoader(reference(path)),
+		path:       localPath(path),
+	}
+}
+
+func reference(path string) string {
 	if loaderPrefixPattern.MatchString(path) {
-		ref = path
-	} else {
-		// gojsonschema has a strange "reference must be canonical" error if the schema path is the current directory
-		if filepath.Dir(path) == "." {
-			path = "./" + path
-		}
-		ref = filePrefix + path
+		return path
 	}
+	// gojsonschema has a strange "reference must be canonical" error if the schema path is the current directory
+	if filepath.Dir(path) == "." {
+		path = "./" + path
+	}
+	return filePrefix + path
+}
+
+// The path of a local file, without the prefix and fragment, or "" if it's remote
+func localPath(path string) string {
+	if !loaderPrefixPattern.MatchString(path) {
+		return path
+	}
+	if !strings.HasPrefix(path, filePrefix) {
+		return ""
+	}
+	// not parsed as a URL, since relative paths like file://./schema.json aren't valid ones
+	local, _, _ := strings.Cut(strings.TrimPrefix(path, filePrefix), "#")
+	if unescaped, err := url.PathUnescape(local); err == nil {
+		local = unescaped
+	}
+	return local
+}
+
+// Load the schema to validate against. gojsonschema only knows draft-07, so the schema is converted to it first,
+// otherwise 2020-12 keywords like prefixItems are ignored (and items: false rejects every tuple). Only the root
+// document is converted: $refs into other files are left as they are, so those files have to stay as they are too
+func schemaLoader(path string) gojsonschema.JSONLoader {
+	loader := &fileLoader{
+		JSONLoader: gojsonschema.NewReferenceLoader(reference(path)),
+		path:       localPath(path),
+	}
+	loader.root = documentURL(loader)
+	return loader
+}
+
+// fileLoader loads local YAML files itself, and leaves everything else to the gojsonschema reference loader. It
+// creates the loaders for $refs in the schema too, so those can be YAML as well
+type fileLoader struct {
+	gojsonschema.JSONLoader
+	path string
+	// the URL of the root schema, which is converted to draft-07. gojsonschema loads the root schema again through
+	// the loader factory, so it's carried along to tell which document that is
+	root string
+}
+
+func (l *fileLoader) LoadJSON() (interface{}, error) {
+	value, err := l.load()
+	if err != nil || l.root == "" || documentURL(l) != l.root {
+		return value, err
+	}
+	// boolean schemas have nothing to convert
+	if _, isObject := value.(map[string]any); !isObject {
+		return value, nil
+	}
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := draft.Convert(bytes, draft.Draft7)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(converted)
+}
+
+// The URL of the document a loader loads, without the fragment
+func documentURL(loader gojsonschema.JSONLoader) string {
+	ref, err := loader.JsonReference()
+	if err != nil || ref.GetUrl() == nil {
+		return ""
+	}
+	documentURL := *ref.GetUrl()
+	documentURL.Fragment = ""
+	return documentURL.String()
+}
+
+func (l *fileLoader) load() (interface{}, error) {
+	if l.path == "" {
+		return l.JSONLoader.LoadJSON()
+	}
+	content, err := os.ReadFile(l.path)
+	if err != nil {
+		return nil, err
+	}
+	if !isYAML(l.path, content) {
+		return l.JSONLoader.LoadJSON()
+	}
+
+	documents, err := parseYAML(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", l.path, err)
+	}
+	if len(documents) != 1 {
+		return nil, fmt.Errorf("%s has %d YAML documents, a schema has to be a single document", l.path, len(documents))
+	}
+	// gojsonschema expects numbers in schemas as json.Number, the way it loads JSON
+	return jsonValue(documents[0].value)
+}
+
+func jsonValue(value any) (any, error) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(bytes)
+}
+
+func decodeJSON(bytes []byte) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	var decoded any
+	return decoded, decoder.Decode(&decoded)
+}
+
+func (l *fileLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return loaderFactory{root: l.root}
+}
+
+type loaderFactory struct {
+	root string
+}
 
-	return gojsonschema.NewReferenceLoader(ref)
+func (f loaderFactory) New(source string) gojsonschema.JSONLoader {
+	loader := Loader(source).(*fileLoader)
+	loader.root = f.root
+	return loader
 }
@@ -0,0 +1,90 @@
+package validate
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+
+	hcl "github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Parse a .tfvars file into an object of the variables it sets. Like OpenTofu, the values can't reference variables
+// or call functions
+func parseTFVars(path string, content []byte) (parsedDocument, error) {
+	file, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return parsedDocument{}, diags
+	}
+	body, _ := file.Body.(*hclsyntax.Body)
+	attributes, diags := body.JustAttributes()
+	if diags.HasErrors() {
+		return parsedDocument{}, diags
+	}
+
+	values := map[string]any{}
+	document := parsedDocument{
+		value:     values,
+		positions: map[string]*result.Range{rootContext: result.HCLRange(body.Range())},
+	}
+	for name, attribute := range attributes {
+		value, valueDiags := attribute.Expr.Value(nil)
+		if valueDiags.HasErrors() {
+			return parsedDocument{}, valueDiags
+		}
+		values[name] = ctyValue(value)
+		tfvarsPositions(attribute.Expr, []string{rootContext, name}, document.positions)
+	}
+	return document, nil
+}
+
+func tfvarsPositions(expr hcl.Expression, context []string, positions map[string]*result.Range) {
+	positions[contextKey(context)] = result.HCLRange(expr.Range())
+	switch typed := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		for _, item := range typed.Items {
+			key, diags := item.KeyExpr.Value(nil)
+			if diags.HasErrors() || key.IsNull() || key.Type() != cty.String {
+				continue
+			}
+			tfvarsPositions(item.ValueExpr, append(slices.Clip(context), key.AsString()), positions)
+		}
+	case *hclsyntax.TupleConsExpr:
+		for index, item := range typed.Exprs {
+			tfvarsPositions(item, append(slices.Clip(context), strconv.Itoa(index)), positions)
+		}
+	}
+}
+
+// The value JSON would have for a cty value. Numbers are kept exact, the way gojsonschema loads JSON
+func ctyValue(value cty.Value) any {
+	if value.IsNull() {
+		return nil
+	}
+	valueType := value.Type()
+	switch {
+	case valueType == cty.String:
+		return value.AsString()
+	case valueType == cty.Number:
+		return json.Number(value.AsBigFloat().Text('f', -1))
+	case valueType == cty.Bool:
+		return value.True()
+	case valueType.IsListType() || valueType.IsSetType() || valueType.IsTupleType():
+		items := []any{}
+		for iterator := value.ElementIterator(); iterator.Next(); {
+			_, item := iterator.Element()
+			items = append(items, ctyValue(item))
+		}
+		return items
+	case valueType.IsMapType() || valueType.IsObjectType():
+		mapping := map[string]any{}
+		for iterator := value.ElementIterator(); iterator.Next(); {
+			key, item := iterator.Element()
+			mapping[key.AsString()] = ctyValue(item)
+		}
+		return mapping
+	}
+	return nil
+}
@@ -1,13 +1,125 @@
 package validate
 
 import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
 	"github.com/xeipuuv/gojsonschema"
 )
 
+// DocumentResult is the result of validating one document. A YAML file can have several documents
+type DocumentResult struct {
+	*gojsonschema.Result
+	Path string
+	// Index of the document in the file, from 0
+	Index int
+
+	positions map[string]*result.Range
+}
+
 // Validate the input object against the schema
 func Validate(schemaPath string, documentPath string) (*gojsonschema.Result, error) {
-	sl := Loader(schemaPath)
-	dl := Loader(documentPath)
+	results, err := ValidateDocuments(schemaPath, documentPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("%s has %d documents, use ValidateDocuments to validate all of them", documentPath, len(results))
+	}
+	return results[0].Result, nil
+}
+
+// ValidateDocuments validates every document in the file against the schema. The schema can be JSON or YAML. The
+// document can also be a parameter file (.tfvars, .bicepparam or ARM parameters.json), which is validated as the
+// object of the values it sets
+func ValidateDocuments(schemaPath string, documentPath string) ([]*DocumentResult, error) {
+	schema, err := gojsonschema.NewSchema(schemaLoader(schemaPath))
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := loadDocuments(documentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*DocumentResult{}
+	for index, document := range documents {
+		res, validateErr := schema.Validate(document.loader)
+		if validateErr != nil {
+			return nil, validateErr
+		}
+		results = append(results, &DocumentResult{
+			Result:    withoutRequiredErrors(res, document.deferred),
+			Path:      documentPath,
+			Index:     index,
+			positions: document.positions,
+		})
+	}
+	return results, nil
+}
+
+// Diagnostics reports the validation errors, pointing at where they are in the document when it's known
+func (r *DocumentResult) Diagnostics() []result.Diagnostic {
+	errs := r.Errors()
+	diags := result.ValidationDiagnostics(r.Path, errs)
+	for index, err := range errs {
+		diags[index].Range = r.positions[err.Context().String("\x00")]
+	}
+	return diags
+}
+
+// Drops the errors about the top level properties being missing, for values that are set but left out of the
+// document since they're only known when deploying
+func withoutRequiredErrors(res *gojsonschema.Result, deferred []string) *gojsonschema.Result {
+	if len(deferred) == 0 {
+		return res
+	}
+	filtered := &gojsonschema.Result{}
+	for _, err := range res.Errors() {
+		property, _ := err.Details()["property"].(string)
+		if err.Type() == "required" && err.Context().String() == rootContext && slices.Contains(deferred, property) {
+			continue
+		}
+		filtered.AddError(err, err.Details())
+	}
+	return filtered
+}
+
+type document struct {
+	loader    gojsonschema.JSONLoader
+	positions map[string]*result.Range
+	deferred  []string
+}
+
+func loadDocuments(path string) ([]document, error) {
+	local := localPath(path)
+	if local == "" {
+		return []document{{loader: Loader(path)}}, nil
+	}
+
+	content, err := os.ReadFile(local)
+	if err != nil {
+		return nil, err
+	}
+	docFormat := documentFormat(local, content)
+	if docFormat == formatJSON {
+		return []document{{loader: Loader(path)}}, nil
+	}
 
-	return gojsonschema.Validate(sl, dl)
+	parsedDocuments, err := parseDocuments(docFormat, local, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", local, err)
+	}
+	documents := []document{}
+	for _, parsed := range parsedDocuments {
+		documents = append(documents, document{
+			loader:    gojsonschema.NewGoLoader(parsed.value),
+			positions: parsed.positions,
+			deferred:  parsed.deferred,
+		})
+	}
+	return documents, nil
 }
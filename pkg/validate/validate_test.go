@@ -3,7 +3,9 @@ package validate_test
 import (
 	"testing"
 
+	"github.com/massdriver-cloud/airlock/pkg/result"
 	"github.com/massdriver-cloud/airlock/pkg/validate"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidate(t *testing.T) {
@@ -26,6 +28,72 @@ func TestValidate(t *testing.T) {
 			documentPath: "testdata/invalid-document.json",
 			want:         false,
 		},
+		{
+			name:         "ValidYAMLDocument",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/valid-document.yaml",
+			want:         true,
+		},
+		{
+			name:         "InvalidYAMLDocument",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/invalid-document.yaml",
+			want:         false,
+		},
+		{
+			name:         "ValidTFVarsDocument",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/valid-document.tfvars",
+			want:         true,
+		},
+		{
+			name:         "InvalidTFVarsDocument",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/invalid-document.tfvars",
+			want:         false,
+		},
+		{
+			name:         "ValidBicepParamDocument",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/valid-document.bicepparam",
+			want:         true,
+		},
+		{
+			name:         "InvalidBicepParamDocument",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/invalid-document.bicepparam",
+			want:         false,
+		},
+		{
+			name:         "ValidARMParameters",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/valid-parameters.json",
+			want:         true,
+		},
+		{
+			name:         "ARMParametersKeyVaultReference",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/reference-parameters.json",
+			want:         true,
+		},
+		{
+			name:         "InvalidARMParameters",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/invalid-parameters.json",
+			want:         false,
+		},
+		{
+			name:         "ValidTuple",
+			schemaPath:   "../opentofu/testdata/opentofu/tuple/schema.json",
+			documentPath: "testdata/valid-tuple.tfvars",
+			want:         true,
+		},
+		{
+			name:         "YAMLSchemaWithRef",
+			schemaPath:   "testdata/schema.yaml",
+			documentPath: "testdata/sized-document.yaml",
+			want:         false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -41,3 +109,222 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDocuments(t *testing.T) {
+	type test struct {
+		name         string
+		schemaPath   string
+		documentPath string
+		want         [][]result.Diagnostic
+	}
+	tests := []test{
+		{
+			name:         "positions",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/invalid-document.yaml",
+			want: [][]result.Diagnostic{
+				{
+					{
+						Path:    "dimensions.height",
+						Code:    "invalid_type",
+						Message: "Invalid type. Expected: integer, given: string",
+						Level:   result.Error,
+						File:    "testdata/invalid-document.yaml",
+						Range:   &result.Range{Start: result.Position{Line: 4, Column: 11}, End: result.Position{Line: 4, Column: 15}},
+					},
+					{
+						Path:    "tags.1",
+						Code:    "invalid_type",
+						Message: "Invalid type. Expected: string, given: integer",
+						Level:   result.Error,
+						File:    "testdata/invalid-document.yaml",
+						Range:   &result.Range{Start: result.Position{Line: 10, Column: 5}, End: result.Position{Line: 10, Column: 6}},
+					},
+				},
+			},
+		},
+		{
+			name:         "multiple documents",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/multi-document.yaml",
+			want: [][]result.Diagnostic{
+				{},
+				{
+					{
+						Path:    "checked",
+						Code:    "invalid_type",
+						Message: "Invalid type. Expected: boolean, given: string",
+						Level:   result.Error,
+						File:    "testdata/multi-document.yaml",
+						Range:   &result.Range{Start: result.Position{Line: 10, Column: 10}, End: result.Position{Line: 10, Column: 15}},
+					},
+				},
+			},
+		},
+		{
+			name:         "tfvars positions",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/invalid-document.tfvars",
+			want: [][]result.Diagnostic{
+				{
+					{
+						Path:    "dimensions.height",
+						Code:    "invalid_type",
+						Message: "Invalid type. Expected: integer, given: string",
+						Level:   result.Error,
+						File:    "testdata/invalid-document.tfvars",
+						Range:   &result.Range{Start: result.Position{Line: 4, Column: 12}, End: result.Position{Line: 4, Column: 18}},
+					},
+					{
+						Path:    "tags.1",
+						Code:    "invalid_type",
+						Message: "Invalid type. Expected: string, given: integer",
+						Level:   result.Error,
+						File:    "testdata/invalid-document.tfvars",
+						Range:   &result.Range{Start: result.Position{Line: 9, Column: 18}, End: result.Position{Line: 9, Column: 19}},
+					},
+				},
+			},
+		},
+		{
+			name:         "bicepparam positions",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/invalid-document.bicepparam",
+			want: [][]result.Diagnostic{
+				{
+					{
+						Path:    "dimensions.height",
+						Code:    "invalid_type",
+						Message: "Invalid type. Expected: integer, given: string",
+						Level:   result.Error,
+						File:    "testdata/invalid-document.bicepparam",
+						Range:   &result.Range{Start: result.Position{Line: 6, Column: 11}, End: result.Position{Line: 6, Column: 17}},
+					},
+					{
+						Path:    "tags.1",
+						Code:    "invalid_type",
+						Message: "Invalid type. Expected: string, given: integer",
+						Level:   result.Error,
+						File:    "testdata/invalid-document.bicepparam",
+						Range:   &result.Range{Start: result.Position{Line: 13, Column: 3}, End: result.Position{Line: 13, Column: 4}},
+					},
+				},
+			},
+		},
+		{
+			name:         "arm parameters",
+			schemaPath:   "testdata/valid-schema.json",
+			documentPath: "testdata/invalid-parameters.json",
+			want: [][]result.Diagnostic{
+				{
+					{
+						Path:    "dimensions.height",
+						Code:    "invalid_type",
+						Message: "Invalid type. Expected: integer, given: string",
+						Level:   result.Error,
+						File:    "testdata/invalid-parameters.json",
+						Range:   &result.Range{Start: result.Position{Line: 11, Column: 19}, End: result.Position{Line: 11, Column: 25}},
+					},
+					{
+						Path:    "tags.1",
+						Code:    "invalid_type",
+						Message: "Invalid type. Expected: string, given: integer",
+						Level:   result.Error,
+						File:    "testdata/invalid-parameters.json",
+						Range:   &result.Range{Start: result.Position{Line: 24, Column: 25}, End: result.Position{Line: 24, Column: 26}},
+					},
+				},
+			},
+		},
+		{
+			name:         "tuples",
+			schemaPath:   "../opentofu/testdata/opentofu/tuple/schema.json",
+			documentPath: "testdata/invalid-tuple.tfvars",
+			want: [][]result.Diagnostic{
+				{
+					{
+						Path:    "coordinates",
+						Code:    "array_no_additional_items",
+						Message: "No additional items allowed on array",
+						Level:   result.Error,
+						File:    "testdata/invalid-tuple.tfvars",
+						Range:   &result.Range{Start: result.Position{Line: 1, Column: 15}, End: result.Position{Line: 1, Column: 24}},
+					},
+					{
+						Path:    "coordinates",
+						Code:    "array_max_items",
+						Message: "Array must have at most 2 items",
+						Level:   result.Error,
+						File:    "testdata/invalid-tuple.tfvars",
+						Range:   &result.Range{Start: result.Position{Line: 1, Column: 15}, End: result.Position{Line: 1, Column: 24}},
+					},
+					{
+						Path:    "listener.1",
+						Code:    "invalid_type",
+						Message: "Invalid type. Expected: number, given: string",
+						Level:   result.Error,
+						File:    "testdata/invalid-tuple.tfvars",
+						Range:   &result.Range{Start: result.Position{Line: 2, Column: 21}, End: result.Position{Line: 2, Column: 24}},
+					},
+				},
+			},
+		},
+		{
+			name:         "yaml schema",
+			schemaPath:   "testdata/schema.yaml",
+			documentPath: "testdata/sized-document.yaml",
+			want: [][]result.Diagnostic{
+				{
+					{
+						Path:    "size",
+						Code:    "number_gte",
+						Message: "Must be greater than or equal to 1",
+						Level:   result.Error,
+						File:    "testdata/sized-document.yaml",
+						Range:   &result.Range{Start: result.Position{Line: 2, Column: 7}, End: result.Position{Line: 2, Column: 8}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := validate.ValidateDocuments(tc.schemaPath, tc.documentPath)
+			require.NoError(t, err)
+
+			require.Len(t, results, len(tc.want))
+			for index, res := range results {
+				// gojsonschema doesn't report errors in a stable order
+				require.ElementsMatch(t, tc.want[index], res.Diagnostics())
+			}
+		})
+	}
+}
+
+func TestValidateDocumentsParseErrors(t *testing.T) {
+	type test struct {
+		name         string
+		documentPath string
+		want         string
+	}
+	tests := []test{
+		{
+			name:         "tfvars expression",
+			documentPath: "testdata/expression.tfvars",
+			want:         "Function calls not allowed",
+		},
+		{
+			name:         "bicepparam expression",
+			documentPath: "testdata/expression.bicepparam",
+			want:         "line 3, column 14: only literal values can be validated, readEnvironmentVariable is an expression",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := validate.ValidateDocuments("testdata/valid-schema.json", tc.documentPath)
+			require.ErrorContains(t, err, tc.want)
+		})
+	}
+}
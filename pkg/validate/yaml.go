@@ -0,0 +1,144 @@
+package validate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/massdriver-cloud/airlock/pkg/result"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// YAML is detected by the extension, or for other extensions by the content not starting like JSON does
+func isYAML(path string, content []byte) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	case ".json":
+		return false
+	}
+	trimmed := bytes.TrimSpace(content)
+	return len(trimmed) > 0 && trimmed[0] != '{' && trimmed[0] != '['
+}
+
+// Parse every document in a YAML stream into the values JSON would have. An empty file is a single null document
+func parseYAML(content []byte) ([]parsedDocument, error) {
+	documents := []parsedDocument{}
+	lines := strings.Split(string(content), "\n")
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		document := parsedDocument{positions: map[string]*result.Range{}}
+		document.value, err = yamlValue(lines, &node, []string{rootContext}, document.positions)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+
+	if len(documents) == 0 {
+		documents = append(documents, parsedDocument{positions: map[string]*result.Range{}})
+	}
+	return documents, nil
+}
+
+func yamlValue(lines []string, node *yaml.Node, context []string, positions map[string]*result.Range) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlValue(lines, node.Content[0], context, positions)
+	case yaml.AliasNode:
+		return yamlValue(lines, node.Alias, context, positions)
+	}
+
+	positions[contextKey(context)] = result.YAMLRange(lines, node)
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		return yamlMapping(lines, node, context, positions)
+	case yaml.SequenceNode:
+		items := make([]any, len(node.Content))
+		for index, itemNode := range node.Content {
+			item, err := yamlValue(lines, itemNode, append(slices.Clip(context), strconv.Itoa(index)), positions)
+			if err != nil {
+				return nil, err
+			}
+			items[index] = item
+		}
+		return items, nil
+	default:
+		return yamlScalar(node)
+	}
+}
+
+func yamlMapping(lines []string, node *yaml.Node, context []string, positions map[string]*result.Range) (map[string]any, error) {
+	mapping := map[string]any{}
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		keyNode, valueNode := node.Content[index], node.Content[index+1]
+
+		// '<<: *defaults' merges the keys of another mapping in, without overriding the ones set here
+		if keyNode.Tag == "!!merge" {
+			mergedPositions := map[string]*result.Range{}
+			merged, err := yamlValue(lines, valueNode, context, mergedPositions)
+			if err != nil {
+				return nil, err
+			}
+			for key, position := range mergedPositions {
+				if _, exists := positions[key]; !exists {
+					positions[key] = position
+				}
+			}
+			mergedMapping, ok := merged.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("line %d: only mappings can be merged", keyNode.Line)
+			}
+			for key, value := range mergedMapping {
+				if _, exists := mapping[key]; !exists {
+					mapping[key] = value
+				}
+			}
+			continue
+		}
+
+		value, err := yamlValue(lines, valueNode, append(slices.Clip(context), keyNode.Value), positions)
+		if err != nil {
+			return nil, err
+		}
+		mapping[keyNode.Value] = value
+	}
+	return mapping, nil
+}
+
+func yamlScalar(node *yaml.Node) (any, error) {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	switch typed := value.(type) {
+	case time.Time:
+		// JSON doesn't have timestamps, so they're validated as the string they were written as
+		return node.Value, nil
+	case float64:
+		if math.IsInf(typed, 0) || math.IsNaN(typed) {
+			return nil, fmt.Errorf("line %d: %s can't be represented in JSON", node.Line, node.Value)
+		}
+	}
+	return value, nil
+}